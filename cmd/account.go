@@ -1,26 +1,31 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"github.com/IBAX-io/go-ibax-sdk/packages/pkg/converter"
 	"github.com/IBAX-io/go-ibax/packages/common/crypto"
 	"github.com/IBAX-io/ibax-cli/conf"
 	"github.com/IBAX-io/ibax-cli/models"
+	"github.com/IBAX-io/ibax-cli/packages/clierr"
 	"github.com/IBAX-io/ibax-cli/packages/consts"
+	"github.com/IBAX-io/ibax-cli/packages/keystore"
 	"github.com/IBAX-io/ibax-cli/packages/parameter"
 	"github.com/pkg/errors"
-	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
 
 const fileMode = 0600
 
+var plainKey bool
+
 var (
 	accountCmd = &cobra.Command{
 		Use:   "account",
@@ -38,23 +43,66 @@ var (
 		Short: "create a new account",
 		Long: `
 Creates a new account and prints the address and keyId and publicKey.
+The private key is saved in a keystore encrypted with a passphrase, unless --plain is set.
+The passphrase is read from the IBAX_PASSPHRASE environment variable or the passphrase_file config, otherwise prompted.
+The key is generated with the config cryptoer and hasher, or the --cryptoer and --hasher flags, and tagged with them.
+With --mnemonic the key is derived at --path from a new BIP-39 mnemonic, which is printed or saved to --mnemonic-file.
+The backup warning is written to stderr.
+
+Result:
+	{
+		"path": "str",					(string,optional) Derivation path, with --mnemonic
+		"address": "str",				(string) Account Address: "xxxx-xxxx-xxxx-xxxx-xxxx"
+		"key_id": n,					(number) Key Id
+		"public_key": "str",			(string) Public Key
+		"private_key_file": "str",		(string) Path of the private key or keystore file
+		"public_key_file": "str",		(string) Path of the public key file
+		"encrypted": bool,				(boolean) true if the private key is saved in a keystore
+		"cryptoer": "str",				(string) Key algorithm
+		"hasher": "str",				(string) Hash algorithm of the address
+		"mnemonic": "str",				(string,optional) The mnemonic, with --mnemonic and without --mnemonic-file
+		"mnemonic_file": "str"			(string,optional) Path of the mnemonic file
+	}
 `,
-		PreRun:     loadConfigPre,
-		Run:        accountNew,
+		PreRunE:    loadConfigPre,
+		RunE:       accountNew,
 		SuggestFor: []string{"new"},
-		Example:    `./ibax-cli account new`,
+		Example:    "./ibax-cli account new\n./ibax-cli account new --mnemonic --words=24 --mnemonic-file=./mnemonic",
 	}
 
 	accountListCmd = &cobra.Command{
 		Use:   "list",
 		Short: "Print information of existing accounts",
 		Long: `
-Print a short information of all accounts
+Print a short information of all accounts in the keys directory.
+The private key (or keystore) and public key files are paired by their time prefix,
+and the private key is checked to derive the stored public key.
+Each key is checked with its algorithm tag, untagged keys of older versions use the --cryptoer and --hasher flags or the config.
+
+Returns a json array of accounts.
+Result:
+	[
+		{
+			"index": n,						(number) Account index
+			"address": "str",				(string) Account Address: "xxxx-xxxx-xxxx-xxxx-xxxx"
+			"key_id": n,					(number) Key Id
+			"public_key": "str",			(string) Public Key
+			"private_key_file": "str",		(string) Path of the private key or keystore file
+			"public_key_file": "str",		(string) Path of the public key file
+			"created": "str",				(string) Creation time, ISO-8601
+			"encrypted": bool,				(boolean) true if the private key is saved in a keystore
+			"cryptoer": "str",				(string) Key algorithm
+			"hasher": "str",				(string) Hash algorithm of the address
+			"tagged": bool,					(boolean) false if the algorithm is assumed for an untagged key
+			"status": "str",				(string) ok | mismatch | orphaned_public_key | orphaned_private_key | invalid
+			"error": "str"					(string,optional) The reason of an invalid or mismatched account
+		}
+	]
 `,
-		PreRun:     loadConfigPre,
-		Run:        accountList,
+		PreRunE:    loadConfigPre,
+		RunE:       accountList,
 		SuggestFor: []string{"list"},
-		Example:    `./ibax-cli account list`,
+		Example:    "./ibax-cli account list\n./ibax-cli account list -o table",
 	}
 
 	addressToKeyIdCmd = &cobra.Command{
@@ -69,8 +117,8 @@ Result:
 	KeyId			(string) Key Id
 `,
 
-		PreRun:     loadConfigPre,
-		Run:        addressToKeyId,
+		PreRunE:    loadConfigPre,
+		RunE:       addressToKeyId,
 		SuggestFor: []string{"addressToKeyId"},
 		Example:    `./ibax-cli account addressToKeyId [address]`,
 	}
@@ -87,11 +135,11 @@ Returns converted address
 Result:
 	Address			(string) Account Address: "xxxx-xxxx-xxxx-xxxx-xxxx"
 `,
-		PreRun: func(cmd *cobra.Command, args []string) {
+		PreRunE: func(cmd *cobra.Command, args []string) error {
 			keyId = 0
-			loadConfigPre(cmd, args)
+			return loadConfigPre(cmd, args)
 		},
-		Run:        keyIdToAddress,
+		RunE:       keyIdToAddress,
 		SuggestFor: []string{"keyIdToAddress --keyId="},
 		Example:    `./ibax-cli account keyIdToAddress --keyId=[KeyId]`,
 	}
@@ -103,12 +151,16 @@ Result:
 Request:
 	publicKey			(string) Public Key
 
+The address is hashed with the config hasher, or the --hasher flag.
 Returns converted address
 Result:
-	Address			(string) Account Address: "xxxx-xxxx-xxxx-xxxx-xxxx"
+	{
+		"key_id": "str",		(string) Key Id
+		"address": "str"		(string) Account Address: "xxxx-xxxx-xxxx-xxxx-xxxx"
+	}
 `,
-		PreRun:     loadConfigPre,
-		Run:        publicKeyToAddress,
+		PreRunE:    loadConfigPre,
+		RunE:       publicKeyToAddress,
 		SuggestFor: []string{"publicKeyToAddress"},
 		Example:    `./ibax-cli account publicKeyToAddress [publicKey]`,
 	}
@@ -129,8 +181,8 @@ Result:
 		"account": "str"			(string) Account Address: "xxxx-xxxx-xxxx-xxxx-xxxx"
 	}
 `,
-		PreRun:     loadConfigPre,
-		Run:        accountInfo,
+		PreRunE:    loadConfigPre,
+		RunE:       accountInfo,
 		SuggestFor: []string{"info"},
 		Example:    `./ibax-cli account info`,
 	}
@@ -146,6 +198,9 @@ You must BACKUP your key file! Without the key, it's impossible to access accoun
 func init() {
 	accountListCmd.Flags().StringVar(&conf.Config.DirPathConf.KeysDir, "keysDir", "", "Keys Directory")
 	accountNewCmd.Flags().StringVar(&conf.Config.DirPathConf.KeysDir, "keysDir", "", "Keys Directory")
+	accountNewCmd.Flags().BoolVar(&plainKey, "plain", false, "save the private key unencrypted")
+
+	addAlgorithmFlags(accountNewCmd, accountListCmd, publicKeyToAddressCmd)
 
 	keyIdToAddressCmd.Flags().Int64Var(&keyId, "keyId", 0, "Key Id")
 	keyIdToAddressCmd.MarkFlagRequired("keyId")
@@ -163,27 +218,6 @@ func createFile(filename string, data []byte) error {
 	return os.WriteFile(filename, data, fileMode)
 }
 
-func createKeyPair(privFilename, pubFilename string) (priv, pub []byte, err error) {
-	priv, pub, err = crypto.GenKeyPair()
-	if err != nil {
-		log.WithError(err).Error("generate keys")
-		return
-	}
-
-	err = createFile(privFilename, []byte(hex.EncodeToString(priv)))
-	if err != nil {
-		log.WithFields(log.Fields{"error": err, "path": privFilename}).Error("creating private key")
-		return
-	}
-
-	err = createFile(pubFilename, []byte(crypto.PubToHex(pub)))
-	if err != nil {
-		log.WithFields(log.Fields{"error": err, "path": pubFilename}).Error("creating public key")
-		return
-	}
-	return
-}
-
 func toISO8601(t time.Time) string {
 	var tz string
 	name, offset := t.Zone()
@@ -196,148 +230,343 @@ func toISO8601(t time.Time) string {
 		t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), tz)
 }
 
-func accountNew(cmd *cobra.Command, params []string) {
+func accountNew(cmd *cobra.Command, params []string) error {
 	err := cobra.NoArgs(cmd, params)
 	if err != nil {
-		log.Infof("no parameters required: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "no parameters required")
 	}
-	nowTimeStr := toISO8601(time.Now())
-	privateKeyName := filepath.Join(conf.Config.DirPathConf.KeysDir, fmt.Sprintf("%s-UTC-%s", nowTimeStr, consts.PrivateKeyFilename))
-	publicKeyName := filepath.Join(conf.Config.DirPathConf.KeysDir, fmt.Sprintf("%s-UTC-%s", nowTimeStr, consts.PublicKeyFilename))
-	_, publicKey, err := createKeyPair(
-		privateKeyName,
-		publicKeyName,
-	)
-	if err != nil {
-		log.Infof("account new failed: %s", err.Error())
-		return
+	var passphrase string
+	if !plainKey {
+		passphrase, err = models.NewPassphrase("Passphrase: ")
+		if err != nil {
+			return clierr.Wrap(clierr.Argument, err, "passphrase invalid")
+		}
 	}
-	keyId := crypto.Address(publicKey)
-	address := converter.AddressToString(keyId)
+	if useMnemonic {
+		return accountNewMnemonic(passphrase)
+	}
+	algo := commandAlgorithm()
+	if err := algo.validate(); err != nil {
+		return clierr.Wrap(clierr.Argument, err, "algorithm invalid")
+	}
+	return withAlgorithm(algo, func() error {
+		privateKey, publicKey, err := crypto.GenKeyPair()
+		if err != nil {
+			return clierr.Wrap(clierr.Unknown, err, "generate keys")
+		}
+		privateKeyName, publicKeyName, err := saveAccount(privateKey, publicKey, passphrase, algo)
+		if err != nil {
+			return clierr.Wrap(clierr.Unknown, err, "account new failed")
+		}
+		fmt.Fprint(os.Stderr, newAccountWarning)
+		return printResult(newSavedAccount(privateKeyName, publicKeyName, publicKey, algo))
+	})
+}
 
-	fmt.Printf("Path of the private key file: %s\n", privateKeyName)
-	fmt.Printf("Path of the public key file: %s\n", publicKeyName)
-	fmt.Printf("Public Key: %s\n", crypto.PubToHex(publicKey))
-	fmt.Printf("KeyId: %d\n", keyId)
-	fmt.Printf("address: %s\n", address)
-	fmt.Printf("%s\n", newAccountWarning)
+// savedAccount is the result of the commands saving a key to the keys directory
+type savedAccount struct {
+	Path           string `json:"path,omitempty"`
+	Address        string `json:"address"`
+	KeyId          int64  `json:"key_id"`
+	PublicKey      string `json:"public_key"`
+	PrivateKeyFile string `json:"private_key_file,omitempty"`
+	PublicKeyFile  string `json:"public_key_file,omitempty"`
+	Encrypted      bool   `json:"encrypted"`
+	Cryptoer       string `json:"cryptoer"`
+	Hasher         string `json:"hasher"`
+	Skipped        bool   `json:"skipped,omitempty"`
 }
 
-func accountList(cmd *cobra.Command, params []string) {
-	err := cobra.NoArgs(cmd, params)
-	if err != nil {
-		log.Infof("no parameters required: %s", err.Error())
-		return
+// newSavedAccount returns the account of the key files saved by saveAccount, it's called with the algorithm algo
+func newSavedAccount(privateKeyName, publicKeyName string, pub []byte, algo keyAlgorithm) *savedAccount {
+	keyId := crypto.Address(pub)
+	return &savedAccount{
+		Address:        converter.AddressToString(keyId),
+		KeyId:          keyId,
+		PublicKey:      crypto.PubToHex(pub),
+		PrivateKeyFile: privateKeyName,
+		PublicKeyFile:  publicKeyName,
+		Encrypted:      strings.HasSuffix(privateKeyName, consts.KeystoreFilename),
+		Cryptoer:       algo.Cryptoer,
+		Hasher:         algo.Hasher,
 	}
-	dir, err := os.ReadDir(filepath.Join(conf.Config.DirPathConf.KeysDir))
+}
+
+// account status reported by account list
+const (
+	accountOk                 = "ok"
+	accountMismatch           = "mismatch"             // the private key doesn't derive the public key
+	accountOrphanedPublicKey  = "orphaned_public_key"  // public key file without private key
+	accountOrphanedPrivateKey = "orphaned_private_key" // private key or keystore without public key file
+	accountInvalid            = "invalid"              // a key file can't be read or decoded
+)
+
+// keysFileTimeLayout is the time prefix of the key file names, see toISO8601
+const keysFileTimeLayout = "2006-01-02T15-04-05.000000000Z"
+
+type accountFiles struct {
+	Index          int    `json:"index"`
+	Address        string `json:"address"`
+	KeyId          int64  `json:"key_id"`
+	PublicKey      string `json:"public_key"`
+	PrivateKeyFile string `json:"private_key_file"`
+	PublicKeyFile  string `json:"public_key_file"`
+	Created        string `json:"created"`
+	Encrypted      bool   `json:"encrypted"`
+	Cryptoer       string `json:"cryptoer"`
+	Hasher         string `json:"hasher"`
+	Tagged         bool   `json:"tagged"`
+	Status         string `json:"status"`
+	Error          string `json:"error,omitempty"`
+}
+
+// scanKeysDir pairs the private key, keystore and public key files sharing the same time prefix.
+// Untagged keys are checked with the algorithm algo
+func scanKeysDir(keysDir string, algo keyAlgorithm) ([]*accountFiles, error) {
+	dir, err := os.ReadDir(keysDir)
 	if err != nil {
-		log.Infof("get keys dir failed:%s", err.Error())
-		return
-	}
-	type accountInfo struct {
-		Path struct {
-			PrivateKey string
-			PublicKey  string
-		}
-		PublicKey string
-		keyId     int64
-		Address   string
+		return nil, err
 	}
-	var accountIndex int = 1
+	var (
+		prefixes []string
+		accounts = make(map[string]*accountFiles)
+	)
 	for _, entry := range dir {
 		if entry.IsDir() {
 			continue
 		}
 		fileName := entry.Name()
-		if strings.HasSuffix(fileName, consts.PublicKeyFilename) {
-			publicKeyName := filepath.Join(conf.Config.DirPathConf.KeysDir, fileName)
-			utcTimePre := fileName[:len(fileName)-len(consts.PublicKeyFilename)]
-			privateKeyName := filepath.Join(conf.Config.DirPathConf.KeysDir, utcTimePre+consts.PublicKeyFilename)
-			publicKey, err := os.ReadFile(publicKeyName)
+		var suffix string
+		for _, s := range []string{consts.PublicKeyFilename, consts.PrivateKeyFilename, consts.KeystoreFilename} {
+			if strings.HasSuffix(fileName, "-UTC-"+s) {
+				suffix = s
+				break
+			}
+		}
+		if suffix == "" {
+			continue
+		}
+		prefix := fileName[:len(fileName)-len(suffix)]
+		info, ok := accounts[prefix]
+		if !ok {
+			info = &accountFiles{}
+			accounts[prefix] = info
+			prefixes = append(prefixes, prefix)
+		}
+		path := filepath.Join(keysDir, fileName)
+		switch suffix {
+		case consts.PublicKeyFilename:
+			info.PublicKeyFile = path
+		case consts.KeystoreFilename:
+			info.PrivateKeyFile = path
+			info.Encrypted = true
+		case consts.PrivateKeyFilename:
+			// a keystore takes precedence over a clear private key with the same prefix
+			if !info.Encrypted {
+				info.PrivateKeyFile = path
+			}
+		}
+		if info.Created == "" {
+			created, err := time.Parse(keysFileTimeLayout, strings.TrimSuffix(prefix, "-UTC-"))
+			if err != nil {
+				if fi, err := entry.Info(); err == nil {
+					created = fi.ModTime()
+				}
+			}
+			info.Created = created.UTC().Format(time.RFC3339)
+		}
+	}
+	sort.Strings(prefixes)
+
+	list := make([]*accountFiles, 0, len(prefixes))
+	for i, prefix := range prefixes {
+		info := accounts[prefix]
+		info.Index = i + 1
+		checkAccountFiles(info, algo)
+		list = append(list, info)
+	}
+	return list, nil
+}
+
+// checkAccountFiles fills the address of the account and checks that the private key derives the stored public key.
+// The public key of a keystore is compared instead, as it can't be unlocked without passphrase
+func checkAccountFiles(info *accountFiles, algo keyAlgorithm) {
+	fail := func(status string, err error) {
+		info.Status = status
+		if err != nil {
+			info.Error = err.Error()
+		}
+	}
+	info.Cryptoer, info.Hasher = algo.Cryptoer, algo.Hasher
+	var (
+		pub, derived, priv []byte
+		tag                keyAlgorithm
+		tagErr             error
+	)
+	if info.PublicKeyFile != "" {
+		data, err := os.ReadFile(info.PublicKeyFile)
+		if err != nil {
+			fail(accountInvalid, err)
+			return
+		}
+		pub, err = hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil {
+			fail(accountInvalid, fmt.Errorf("public key decode failed: %w", err))
+			return
+		}
+	}
+	if info.PrivateKeyFile != "" {
+		data, err := os.ReadFile(info.PrivateKeyFile)
+		if err != nil {
+			fail(accountInvalid, err)
+			return
+		}
+		if info.Encrypted {
+			ks, err := keystore.Parse(data)
+			if err != nil {
+				fail(accountInvalid, err)
+				return
+			}
+			derived, err = hex.DecodeString(ks.PublicKey)
 			if err != nil {
-				fmt.Printf("readFile failed:%s\n", err.Error())
-				continue
+				fail(accountInvalid, fmt.Errorf("keystore public key decode failed: %w", err))
+				return
 			}
-			pub, err := hex.DecodeString(string(publicKey))
+			tag, info.Tagged, tagErr = keystoreAlgorithm(ks)
+		} else {
+			priv, err = hex.DecodeString(strings.TrimSpace(string(data)))
 			if err != nil {
-				fmt.Printf("publicKey[%s] decode failed:%s\n", publicKeyName, err.Error())
-				continue
+				fail(accountInvalid, fmt.Errorf("private key decode failed: %w", err))
+				return
 			}
+		}
+	}
+	if !info.Tagged {
+		keyFile := info.PrivateKeyFile
+		if keyFile == "" {
+			keyFile = info.PublicKeyFile
+		}
+		tag, info.Tagged, tagErr = readKeyAlgorithm(algorithmFilename(keyFile))
+	}
+	if tagErr != nil {
+		fail(accountInvalid, tagErr)
+		return
+	}
+	if info.Tagged {
+		algo = tag
+		info.Cryptoer, info.Hasher = tag.Cryptoer, tag.Hasher
+	}
 
-			keyId := crypto.Address(pub)
-			address := converter.AddressToString(keyId)
-			var info accountInfo
-			info.Path.PublicKey = publicKeyName
-			info.Path.PrivateKey = privateKeyName
-			info.PublicKey = crypto.PubToHex(pub)
-			info.keyId = keyId
-			info.Address = address
-			fmt.Printf("account #%d:%+v\n", accountIndex, info)
-			accountIndex += 1
+	err := withAlgorithm(algo, func() error {
+		if priv != nil {
+			var err error
+			derived, err = crypto.PrivateToPublic(priv)
+			if err != nil {
+				return fmt.Errorf("private key invalid: %w", err)
+			}
+		}
+		key := pub
+		if key == nil {
+			key = derived
 		}
+		info.PublicKey = crypto.PubToHex(key)
+		info.KeyId = crypto.Address(key)
+		info.Address = converter.AddressToString(info.KeyId)
+		return nil
+	})
+	if err != nil {
+		fail(accountInvalid, err)
+		return
+	}
+	switch {
+	case pub == nil:
+		fail(accountOrphanedPrivateKey, nil)
+	case derived == nil:
+		fail(accountOrphanedPublicKey, nil)
+	case !bytes.Equal(crypto.CutPub(pub), crypto.CutPub(derived)):
+		fail(accountMismatch, fmt.Errorf("private key derives %s", crypto.PubToHex(derived)))
+	default:
+		info.Status = accountOk
 	}
-	fmt.Println()
 }
 
-func publicKeyToAddress(cmd *cobra.Command, params []string) {
+func accountList(cmd *cobra.Command, params []string) error {
+	err := cobra.NoArgs(cmd, params)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "no parameters required")
+	}
+	algo := commandAlgorithm()
+	if err := algo.validate(); err != nil {
+		return clierr.Wrap(clierr.Argument, err, "algorithm invalid")
+	}
+	list, err := scanKeysDir(conf.Config.DirPathConf.KeysDir, algo)
+	if err != nil {
+		return clierr.Wrap(clierr.Config, err, "get keys dir failed")
+	}
+	return printResult(list)
+}
+
+func publicKeyToAddress(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	publicKeyStr, err := args.Set(0, true).String()
 	if err != nil {
-		log.Infof("publicKey invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "publicKey invalid")
 	}
 	pub, err := hex.DecodeString(publicKeyStr)
 	if err != nil {
-		log.Infof("publicKey decode failed:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "publicKey decode failed")
 	}
-	keyId := crypto.Address(pub)
-	address := converter.AddressToString(keyId)
-
-	fmt.Printf("\nkeyId: %d,account address: %s\n", keyId, address)
+	var keyId int64
+	err = withAlgorithm(commandAlgorithm(), func() error {
+		keyId = crypto.Address(pub)
+		return nil
+	})
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "algorithm invalid")
+	}
+	return printResult(struct {
+		KeyId   string `json:"key_id"`
+		Address string `json:"address"`
+	}{
+		KeyId:   strconv.FormatInt(keyId, 10),
+		Address: converter.AddressToString(keyId),
+	})
 }
 
-func addressToKeyId(cmd *cobra.Command, params []string) {
+func addressToKeyId(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	address, err := args.Set(0, true).String()
 	if err != nil {
-		log.Infof("address invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "address invalid")
 	}
 	keyId := converter.StringToAddress(address)
 	if keyId == 0 {
-		log.Infof("address invalid:%s", address)
-		return
+		return clierr.New(clierr.Argument, "address invalid:%s", address)
 	}
 
-	fmt.Printf("\n%d\n", keyId)
+	return printResult(strconv.FormatInt(keyId, 10))
 }
 
-func keyIdToAddress(cmd *cobra.Command, params []string) {
+func keyIdToAddress(cmd *cobra.Command, params []string) error {
 	err := cobra.NoArgs(cmd, params)
 	if err != nil {
-		log.Infof("no parameters required: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "no parameters required")
 	}
 	address := converter.AddressToString(keyId)
 	if address == "" {
-		fmt.Printf("invalid Key Id:%d\n", keyId)
-		return
+		return clierr.New(clierr.Argument, "invalid Key Id:%d", keyId)
 	}
 	if converter.StringToAddress(address) == 0 {
-		log.Infof("KeyId invalid:%d", keyId)
-		return
+		return clierr.New(clierr.Argument, "KeyId invalid:%d", keyId)
 	}
 
-	fmt.Printf("\n%s\n", address)
+	return printResult(address)
 }
 
-func accountInfo(cmd *cobra.Command, params []string) {
+func accountInfo(cmd *cobra.Command, params []string) error {
 	err := cobra.NoArgs(cmd, params)
 	if err != nil {
-		log.Infof("no parameters required: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "no parameters required")
 	}
 	type accountInfo struct {
 		PublicKey   string `json:"public_key"`
@@ -352,10 +581,5 @@ func accountInfo(cmd *cobra.Command, params []string) {
 	info.KeyId = cnf.KeyId
 	info.Account = cnf.Account
 
-	str, err := json.MarshalIndent(info, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
-	}
-	fmt.Printf("\n%+v\n", string(str))
+	return printResult(info)
 }
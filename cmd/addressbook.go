@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/IBAX-io/go-ibax-sdk/packages/pkg/converter"
+	"github.com/IBAX-io/ibax-cli/conf"
+	"github.com/IBAX-io/ibax-cli/models"
+	"github.com/IBAX-io/ibax-cli/packages/clierr"
+	"github.com/IBAX-io/ibax-cli/packages/parameter"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+var (
+	addressBookNote string
+
+	addressBookCmd = &cobra.Command{
+		Use:   "addressbook",
+		Short: "Manage the aliases of account addresses",
+		Long: `
+The address book is addressbook.yml next to config.yml. An alias is given as @alias wherever an account address
+is accepted: getBalance, getKeyInfo, getMemberInfo, the recipient of callUtxo and the utxo commands, the
+--account of tx build and the address fields of the contract params. Other params like "@home" are sent as they are.
+An alias starts with a letter, so @1TokensSend stays a contract name.
+`,
+	}
+
+	addressBookAddCmd = &cobra.Command{
+		Use:   "add [alias] [address]",
+		Short: "add an alias of an account address",
+		Long: `
+Request:
+	alias			(string) Alias, a letter followed by letters, digits, '_', '-' or '.', the @ is optional
+	address			(string) Account Address: "xxxx-xxxx-xxxx-xxxx-xxxx", its checksum is checked
+
+An existing alias is replaced.
+Result:
+	{
+		"alias": "str",			(string) @alias
+		"address": "str",		(string) Account Address
+		"key_id": "str",		(string) Key Id
+		"note": "str"			(string) note
+	}
+`,
+		Args:       cobra.ExactArgs(2),
+		RunE:       addressBookAdd,
+		SuggestFor: []string{"add"},
+		Example:    `./ibax-cli addressbook add treasury 0666-7782-xxxx-xxxx-3160 --note="ecosystem treasury"`,
+	}
+
+	addressBookListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "list the aliases",
+		Long: `
+Request:
+	No parameters required
+
+Returns a json array of the aliases in alphabetical order
+Result:
+	[
+		{
+			"alias": "str",				(string) Alias, with @
+			"address": "str",			(string) Account Address
+			"key_id": "str",			(string) Key Id of the address
+			"note": "str"				(string) Note
+		}
+	]
+`,
+		Args:       cobra.NoArgs,
+		RunE:       addressBookList,
+		SuggestFor: []string{"list"},
+		Example:    "./ibax-cli addressbook list\n./ibax-cli addressbook list -o table",
+	}
+
+	addressBookRemoveCmd = &cobra.Command{
+		Use:   "remove [alias]",
+		Short: "remove an alias",
+		Long: `
+Request:
+	alias			(string) Alias, the @ is optional
+
+Returns the removed alias
+Result:
+	{
+		"alias": "str",			(string) @alias
+		"address": "str",		(string) Account Address
+		"key_id": "str",		(string) Key Id
+		"note": "str"			(string) note
+	}
+`,
+		Args:       cobra.ExactArgs(1),
+		RunE:       addressBookRemove,
+		SuggestFor: []string{"remove"},
+		Example:    "./ibax-cli addressbook remove treasury",
+	}
+)
+
+func init() {
+	addressBookCmd.AddCommand(
+		addressBookAddCmd,
+		addressBookListCmd,
+		addressBookRemoveCmd,
+	)
+	for _, subCommand := range addressBookCmd.Commands() {
+		for k, v := range subCommand.SuggestFor {
+			subCommand.SuggestFor[k] = addressBookCmd.Use + " " + v
+			models.AddWordsCompletions(subCommand.SuggestFor)
+		}
+	}
+	addressBookAddCmd.Flags().StringVar(&addressBookNote, "note", "", "note of the address")
+}
+
+func readAddressBook() (*conf.AddressBook, error) {
+	book, err := conf.ReadAddressBook(conf.AddressBookPath())
+	if err != nil {
+		return nil, clierr.Wrap(clierr.Config, err, "loading address book")
+	}
+	return book, nil
+}
+
+func addressBookAdd(cmd *cobra.Command, params []string) error {
+	args := parameter.New(params)
+	alias, err := args.Set(0, true).String()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "alias invalid")
+	}
+	alias = strings.TrimPrefix(alias, conf.AliasPrefix)
+	if err = conf.CheckAliasName(alias); err != nil {
+		return clierr.Wrap(clierr.Argument, err, "alias invalid")
+	}
+	address, err := args.Set(1, true).String()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "address invalid")
+	}
+	keyId := converter.StringToAddress(address)
+	if keyId == 0 {
+		return clierr.New(clierr.Argument, "address [%s] invalid: checksum mismatch or format not supported", address)
+	}
+	book, err := readAddressBook()
+	if err != nil {
+		return err
+	}
+	entry := conf.AddressEntry{Address: converter.AddressToString(keyId), Note: addressBookNote}
+	book.Addresses[alias] = entry
+	err = conf.WriteAddressBook(conf.AddressBookPath(), book)
+	if err != nil {
+		return clierr.Wrap(clierr.Config, err, "Saving address book")
+	}
+	return printResult(newAliasInfo(alias, entry))
+}
+
+type aliasInfo struct {
+	Alias   string `json:"alias"`
+	Address string `json:"address"`
+	KeyId   string `json:"key_id"`
+	Note    string `json:"note"`
+}
+
+func newAliasInfo(alias string, entry conf.AddressEntry) aliasInfo {
+	return aliasInfo{
+		Alias:   conf.AliasPrefix + alias,
+		Address: entry.Address,
+		KeyId:   fmt.Sprint(converter.StringToAddress(entry.Address)),
+		Note:    entry.Note,
+	}
+}
+
+func addressBookList(cmd *cobra.Command, params []string) error {
+	book, err := readAddressBook()
+	if err != nil {
+		return err
+	}
+	list := make([]aliasInfo, 0, len(book.Addresses))
+	for _, alias := range book.Aliases() {
+		list = append(list, newAliasInfo(alias, book.Addresses[alias]))
+	}
+	return printResult(list)
+}
+
+func addressBookRemove(cmd *cobra.Command, params []string) error {
+	args := parameter.New(params)
+	alias, err := args.Set(0, true).String()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "alias invalid")
+	}
+	alias = strings.TrimPrefix(alias, conf.AliasPrefix)
+	book, err := readAddressBook()
+	if err != nil {
+		return err
+	}
+	entry, ok := book.Addresses[alias]
+	if !ok {
+		return clierr.New(clierr.NotFound, "alias [%s%s] not found", conf.AliasPrefix, alias)
+	}
+	delete(book.Addresses, alias)
+	err = conf.WriteAddressBook(conf.AddressBookPath(), book)
+	if err != nil {
+		return clierr.Wrap(clierr.Config, err, "Saving address book")
+	}
+	return printResult(newAliasInfo(alias, entry))
+}
+
+// isAlias reports whether s is written as an alias of the address book
+func isAlias(s string) bool {
+	return strings.HasPrefix(s, conf.AliasPrefix) && conf.CheckAliasName(strings.TrimPrefix(s, conf.AliasPrefix)) == nil
+}
+
+// resolveAccount returns the address of an alias, other accounts are returned as given
+func resolveAccount(account string) (string, error) {
+	if !isAlias(account) {
+		return account, nil
+	}
+	book, err := conf.ReadAddressBook(conf.AddressBookPath())
+	if err != nil {
+		return "", err
+	}
+	entry, ok := book.Addresses[strings.TrimPrefix(account, conf.AliasPrefix)]
+	if !ok {
+		return "", fmt.Errorf("alias [%s] not found in the address book %s", account, conf.AddressBookPath())
+	}
+	return entry.Address, nil
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/ibax-cli/conf"
+	"github.com/IBAX-io/ibax-cli/packages/consts"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+)
+
+// algorithm overrides of the account commands, the config values are used when empty
+var (
+	keyCryptoer string
+	keyHasher   string
+)
+
+// keyAlgorithm is the algorithm tag stored with a key file
+type keyAlgorithm struct {
+	Cryptoer string `json:"cryptoer"`
+	Hasher   string `json:"hasher"`
+}
+
+func (a keyAlgorithm) String() string {
+	return fmt.Sprintf("%s/%s", a.Cryptoer, a.Hasher)
+}
+
+// supportedCryptoers are the key algorithms with a provider, ECC_P512 is defined but not implemented
+var supportedCryptoers = []crypto.AsymAlgo{crypto.AsymAlgo_ECC_P256, crypto.AsymAlgo_ECC_Secp256k1, crypto.AsymAlgo_SM2}
+
+// validate checks the names before they are passed to the crypto package, which exits on an unknown name
+func (a keyAlgorithm) validate() error {
+	v, ok := crypto.AsymAlgo_value[a.Cryptoer]
+	if !ok {
+		return fmt.Errorf("cryptoer [%s] invalid, supported: %s", a.Cryptoer, cryptoerNames())
+	}
+	supported := false
+	for _, c := range supportedCryptoers {
+		if c == crypto.AsymAlgo(v) {
+			supported = true
+		}
+	}
+	if !supported {
+		return fmt.Errorf("cryptoer [%s] is not supported yet, supported: %s", a.Cryptoer, cryptoerNames())
+	}
+	if _, ok := crypto.HashAlgo_value[a.Hasher]; !ok {
+		return fmt.Errorf("hasher [%s] invalid, supported: %s | %s | %s | %s", a.Hasher,
+			crypto.HashAlgo_SHA256, crypto.HashAlgo_KECCAK256, crypto.HashAlgo_SHA3_256, crypto.HashAlgo_SM3)
+	}
+	return nil
+}
+
+func cryptoerNames() string {
+	names := make([]string, len(supportedCryptoers))
+	for i, c := range supportedCryptoers {
+		names[i] = c.String()
+	}
+	return strings.Join(names, " | ")
+}
+
+// configAlgorithm returns the algorithm of the config, unset names keep the defaults of the config command
+func configAlgorithm() keyAlgorithm {
+	a := keyAlgorithm{Cryptoer: conf.Config.Cryptoer, Hasher: conf.Config.Hasher}
+	if a.Cryptoer == "" {
+		a.Cryptoer = crypto.AsymAlgo_ECC_Secp256k1.String()
+	}
+	if a.Hasher == "" {
+		a.Hasher = crypto.HashAlgo_KECCAK256.String()
+	}
+	return a
+}
+
+// commandAlgorithm returns the algorithm of the config with the --cryptoer and --hasher overrides applied
+func commandAlgorithm() keyAlgorithm {
+	a := configAlgorithm()
+	if keyCryptoer != "" {
+		a.Cryptoer = keyCryptoer
+	}
+	if keyHasher != "" {
+		a.Hasher = keyHasher
+	}
+	return a
+}
+
+// initAlgorithm sets the process wide algorithms of the crypto package
+func initAlgorithm(a keyAlgorithm) error {
+	if err := a.validate(); err != nil {
+		return err
+	}
+	crypto.InitAsymAlgo(a.Cryptoer)
+	crypto.InitHashAlgo(a.Hasher)
+	return nil
+}
+
+// withAlgorithm runs fn with the algorithm a, the config algorithm is restored afterwards
+// so that the signing of the client isn't affected in the console
+func withAlgorithm(a keyAlgorithm, fn func() error) error {
+	if err := initAlgorithm(a); err != nil {
+		return err
+	}
+	defer initAlgorithm(configAlgorithm())
+	return fn()
+}
+
+// algorithmFilename returns the tag file of a key file in the keys directory layout, empty for other names.
+// Clear private keys are tagged by this file, keystores hold the tag themselves
+func algorithmFilename(keyFile string) string {
+	for _, suffix := range []string{consts.PrivateKeyFilename, consts.PublicKeyFilename, consts.KeystoreFilename} {
+		if strings.HasSuffix(keyFile, "-UTC-"+suffix) {
+			return strings.TrimSuffix(keyFile, suffix) + consts.AlgorithmFilename
+		}
+	}
+	return ""
+}
+
+// readKeyAlgorithm reads the tag file, ok is false for untagged keys created by older versions
+func readKeyAlgorithm(filename string) (a keyAlgorithm, ok bool, err error) {
+	if filename == "" {
+		return a, false, nil
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return a, false, nil
+		}
+		return a, false, err
+	}
+	err = json.Unmarshal(data, &a)
+	if err != nil {
+		return a, false, fmt.Errorf("algorithm tag %s invalid: %w", filename, err)
+	}
+	return a, true, a.validate()
+}
+
+func addAlgorithmFlags(cmds ...*cobra.Command) {
+	for _, c := range cmds {
+		c.Flags().StringVar(&keyCryptoer, "cryptoer", "", "Key algorithm, overrides the config cryptoer")
+		c.Flags().StringVar(&keyHasher, "hasher", "", "Hash algorithm, overrides the config hasher")
+	}
+}
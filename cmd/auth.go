@@ -1,11 +1,9 @@
 package cmd
 
 import (
-	"context"
-	"encoding/json"
-	"fmt"
 	"github.com/IBAX-io/ibax-cli/conf"
 	"github.com/IBAX-io/ibax-cli/models"
+	"github.com/IBAX-io/ibax-cli/packages/clierr"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -18,58 +16,64 @@ var (
 		Example:    `./ibax-cli getAuthStatus`,
 		SuggestFor: []string{"getAuthStatus"},
 		Args:       cobra.NoArgs,
-		PreRun:     loadConfigPre,
-		Run: func(cmd *cobra.Command, args []string) {
+		PreRunE:    loadConfigPre,
+		RunE: func(cmd *cobra.Command, args []string) error {
 			err := cobra.NoArgs(cmd, args)
 			if err != nil {
-				log.Infof("no parameters required: %s", err.Error())
-				return
+				return clierr.Wrap(clierr.Argument, err, "no parameters required")
 			}
 			result, err := models.Client.GetAuthStatus()
 			if err != nil {
-				log.Infof("Get Authorization Status Failed: %s", err.Error())
-				return
+				return clierr.Wrap(clierr.RPC, err, "Get Authorization Status Failed")
 			}
 			if result == nil {
-				log.Info("Get Authorization Status Result Empty")
-				return
+				return clierr.New(clierr.NotFound, "Get Authorization Status Result Empty")
 			}
-			str, _ := json.MarshalIndent(*result, "", "    ")
-			fmt.Printf("\n%+v\n", string(str))
+			return printResult(*result)
 		},
 	}
 
 	refresh = &cobra.Command{
-		Use:        "refresh",
-		Short:      `refresh config And re-login`,
-		Example:    `./ibax-cli refresh`,
+		Use:   "refresh [profile]",
+		Short: `refresh config And re-login`,
+		Long: `
+Request:
+	profile			(string,optional) profile to switch to, the current profile is kept by default
+
+Re-reads config.yml and logs in again, in the console it switches the profile without restarting.
+`,
+		Example:    "./ibax-cli refresh\n./ibax-cli refresh testnet",
 		SuggestFor: []string{"refresh"},
-		Args:       cobra.NoArgs,
-		Run:        refreshCmd,
+		Args:       cobra.MaximumNArgs(1),
+		RunE:       refreshCmd,
 	}
 )
 
-func loginPre(cmd *cobra.Command, args []string) {
+func loginPre(cmd *cobra.Command, args []string) error {
 	if models.Client != nil {
 		cnf := models.Client.GetConfig()
 		if cnf.Token != "" {
-			models.RefreshToken(cmd)
-			return
+			return models.RefreshToken()
 		}
 		if cnf.PrivateKey == "" {
-			err := fmt.Errorf("private key can't not be empty, Please set in the configuration file:%s", conf.Config.ConfigPath)
-			ctx := cmd.Context()
-			ctx = context.WithValue(ctx, "error", err)
-			cmd.SetContext(ctx)
-			log.Infof(err.Error())
-			return
+			return clierr.New(clierr.Config, "private key can't not be empty, Please set in the configuration file:%s", conf.Config.ConfigPath)
 		}
 	}
-	loadConfigPre(cmd, args)
-	models.Login(cmd)
+	err := loadConfigPre(cmd, args)
+	if err != nil {
+		return err
+	}
+	return models.Login()
 }
 
-func refreshCmd(cmd *cobra.Command, args []string) {
+func refreshCmd(cmd *cobra.Command, args []string) error {
+	name := conf.Config.Profile
+	if profileName != "" {
+		name = profileName
+	}
+	if len(args) > 0 {
+		name = args[0]
+	}
 	models.Client = nil
 	path := conf.Config.ConfigPath
 	rpcConnect := conf.Config.RpcConnect
@@ -83,15 +87,11 @@ func refreshCmd(cmd *cobra.Command, args []string) {
 		DirPathConf: dirPath,
 	}
 	conf.SetDefaultConfig()
-	loadConfigPre(cmd, args)
-	if hasErrorContext(cmd) {
-		cleanErrorContext(cmd)
-		return
-	}
-	loginPre(cmd, args)
-	if hasErrorContext(cmd) {
-		cleanErrorContext(cmd)
-		return
+	profileName = name
+	err := loginPre(cmd, args)
+	if err != nil {
+		return err
 	}
-	fmt.Println("\nRefresh Success!!")
+	log.Infof("Refresh Success!! profile: %s", conf.Config.Profile)
+	return nil
 }
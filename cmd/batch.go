@@ -0,0 +1,494 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/IBAX-io/go-ibax-sdk/packages/request"
+	"github.com/IBAX-io/go-ibax-sdk/packages/response"
+	"github.com/IBAX-io/ibax-cli/models"
+	"github.com/IBAX-io/ibax-cli/packages/clierr"
+	"github.com/IBAX-io/ibax-cli/packages/parameter"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	batchFrom   string
+	batchReport string
+
+	batchCmd = &cobra.Command{
+		Use:   "batch",
+		Short: "Run a plan of contract and UTXO calls",
+	}
+
+	batchRunCmd = &cobra.Command{
+		Use:   "run [PlanFile]",
+		Short: "run the calls of a yaml or json plan in one session",
+		Long: `
+Request:
+	PlanFile			(string) yaml or json plan
+
+Runs the steps of the plan in order, logged in once. The plan:
+	on_error: stop						(string,optional) stop || continue, default stop
+	vars:								(object,optional) values used as ${vars.<name>}
+		app: MyApp
+	steps:
+	  - name: app						(string,optional) step name, default step<n>
+		contract: "@1NewApplication"	(string) contract name, or
+		utxo: TypeTransfer				(string) UTXO type of callUtxo
+		params:							(object,optional) params, tagged values are decoded like callContract
+			Name: ${vars.app}
+		expedite: "1"					(string,optional) expedite of the step
+		on_error: continue				(string,optional) overrides on_error of the plan for the step
+
+Strings of the contract, params and expedite may use ${vars.<name>} and ${steps.<name>.hash|block_id|result}
+of an earlier step, the result is the value returned by the contract, a created id for example.
+
+The report is written to --report after every step. With --from the steps before the given step name or number
+aren't run, their results are read from the report of the previous run.
+
+Returns a json object of the report.
+Result:
+	{
+		"plan": "str",					(string) Plan file
+		"started": "str",				(string) Start time
+		"finished": "str",				(string) End time
+		"steps": [
+			{
+				"step": n,				(number) Step number, from 1
+				"name": "str",			(string) Step name
+				"contract": "str",		(string, optional) Contract name
+				"utxo": "str",			(string, optional) UTXO type
+				"status": "str",		(string) ok || failed || skipped (before --from) || not_run (after a failure)
+				"hash": "str",			(string, optional) Transaction hash
+				"block_id": n,			(number, optional) The block id of the transaction
+				"penalty": n,			(number, optional) 1 if the transaction is penalized
+				"result": "str",		(string, optional) The result of the contract
+				"error": "str"			(string, optional) Why the step failed
+			}
+		]
+	}
+`,
+		Args:       cobra.ExactArgs(1),
+		PreRunE:    loginPre,
+		RunE:       batchRun,
+		SuggestFor: []string{"run"},
+		Example:    "./ibax-cli batch run release.yml --report=release.report.json\n./ibax-cli batch run release.yml --from=tables",
+	}
+)
+
+const (
+	batchStop     = "stop"
+	batchContinue = "continue"
+
+	BatchStepOk      = "ok"
+	BatchStepFailed  = "failed"
+	BatchStepSkipped = "skipped"
+	BatchStepNotRun  = "not_run"
+)
+
+var batchVarRegexp = regexp.MustCompile(`\$\{([^}]*)}`)
+
+type batchPlan struct {
+	OnError string            `yaml:"on_error"`
+	Vars    map[string]string `yaml:"vars"`
+	Steps   []*batchStep      `yaml:"steps"`
+}
+
+type batchStep struct {
+	Name     string         `yaml:"name"`
+	Contract string         `yaml:"contract"`
+	Utxo     string         `yaml:"utxo"`
+	Params   map[string]any `yaml:"params"`
+	Expedite string         `yaml:"expedite"`
+	OnError  string         `yaml:"on_error"`
+}
+
+type batchStepReport struct {
+	Step     int    `json:"step"`
+	Name     string `json:"name"`
+	Contract string `json:"contract,omitempty"`
+	Utxo     string `json:"utxo,omitempty"`
+	Status   string `json:"status"`
+	Hash     string `json:"hash,omitempty"`
+	BlockId  int64  `json:"block_id,omitempty"`
+	Penalty  int64  `json:"penalty,omitempty"`
+	Result   string `json:"result,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+type batchRunReport struct {
+	Plan     string             `json:"plan"`
+	Started  string             `json:"started"`
+	Finished string             `json:"finished"`
+	Steps    []*batchStepReport `json:"steps"`
+}
+
+func init() {
+	batchCmd.AddCommand(batchRunCmd)
+	for _, subCommand := range batchCmd.Commands() {
+		for k, v := range subCommand.SuggestFor {
+			subCommand.SuggestFor[k] = batchCmd.Use + " " + v
+			models.AddWordsCompletions(subCommand.SuggestFor)
+		}
+	}
+	batchRunCmd.Flags().StringVar(&batchFrom, "from", "", "name or number of the step to resume from")
+	batchRunCmd.Flags().StringVar(&batchReport, "report", "", "report file to write (default <PlanFile>.report.json)")
+}
+
+func batchRun(cmd *cobra.Command, params []string) error {
+	args := parameter.New(params)
+	planFile, err := args.Set(0, true).String()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "PlanFile invalid")
+	}
+	plan, err := readBatchPlan(planFile)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "plan invalid")
+	}
+	reportFile := batchReport
+	if reportFile == "" {
+		reportFile = strings.TrimSuffix(planFile, ".yml")
+		reportFile = strings.TrimSuffix(reportFile, ".yaml")
+		reportFile = strings.TrimSuffix(reportFile, ".json") + ".report.json"
+	}
+	from, err := batchFromStep(plan, batchFrom)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "--from invalid")
+	}
+
+	report := &batchRunReport{Plan: planFile, Started: time.Now().UTC().Format(time.RFC3339)}
+	var previous map[string]*batchStepReport
+	if from > 0 {
+		previous, err = readBatchReport(reportFile)
+		if err != nil {
+			return clierr.Wrap(clierr.Argument, err, "read the report of the previous run failed")
+		}
+	}
+	for i, step := range plan.Steps {
+		rep := &batchStepReport{Step: i + 1, Name: step.Name, Contract: step.Contract, Utxo: step.Utxo, Status: BatchStepNotRun}
+		if i < from {
+			rep.Status = BatchStepSkipped
+			if prev, ok := previous[step.Name]; ok && prev.Status == BatchStepOk {
+				rep = prev
+				rep.Step = i + 1
+			}
+		}
+		report.Steps = append(report.Steps, rep)
+	}
+
+	var failed []*batchStepReport
+	var firstErr error
+	for i := from; i < len(plan.Steps); i++ {
+		step, rep := plan.Steps[i], report.Steps[i]
+		log.Infof("step %d/%d %s", i+1, len(plan.Steps), step.Name)
+		err = runBatchStep(plan, report, step, rep)
+		if err != nil {
+			rep.Status = BatchStepFailed
+			rep.Error = err.Error()
+			failed = append(failed, rep)
+			if firstErr == nil {
+				firstErr = err
+			}
+			log.Warnf("step %s failed: %s", step.Name, err)
+		} else {
+			rep.Status = BatchStepOk
+		}
+		if werr := writeBatchReport(reportFile, report); werr != nil {
+			return clierr.Wrap(clierr.Unknown, werr, "save report failed")
+		}
+		if err != nil && batchOnError(plan, step) == batchStop {
+			break
+		}
+	}
+	report.Finished = time.Now().UTC().Format(time.RFC3339)
+	if err = writeBatchReport(reportFile, report); err != nil {
+		return clierr.Wrap(clierr.Unknown, err, "save report failed")
+	}
+	log.Infof("report is saved to %s", reportFile)
+	if err = printResult(report); err != nil {
+		return err
+	}
+	if firstErr != nil {
+		return clierr.Wrap(clierr.KindOf(firstErr), firstErr, fmt.Sprintf("%d step(s) failed, first %s", len(failed), failed[0].Name))
+	}
+	return nil
+}
+
+func readBatchPlan(filename string) (*batchPlan, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	// json is read as yaml
+	var plan batchPlan
+	if err = yaml.Unmarshal(data, &plan); err != nil {
+		return nil, err
+	}
+	if len(plan.Steps) == 0 {
+		return nil, errors.New("no steps")
+	}
+	if plan.OnError == "" {
+		plan.OnError = batchStop
+	}
+	if plan.OnError != batchStop && plan.OnError != batchContinue {
+		return nil, fmt.Errorf("on_error [%s] must be %s or %s", plan.OnError, batchStop, batchContinue)
+	}
+	names := make(map[string]bool, len(plan.Steps))
+	for i, step := range plan.Steps {
+		if step == nil {
+			return nil, fmt.Errorf("step %d empty", i+1)
+		}
+		if step.Name == "" {
+			step.Name = "step" + strconv.Itoa(i+1)
+		}
+		if names[step.Name] {
+			return nil, fmt.Errorf("step %d: name %s is used twice", i+1, step.Name)
+		}
+		if (step.Contract == "") == (step.Utxo == "") {
+			return nil, fmt.Errorf("step %s: one of contract or utxo must be set", step.Name)
+		}
+		if step.Utxo != "" {
+			if _, err := parseUtxoType(step.Utxo); err != nil {
+				return nil, fmt.Errorf("step %s: %w", step.Name, err)
+			}
+		}
+		if step.OnError != "" && step.OnError != batchStop && step.OnError != batchContinue {
+			return nil, fmt.Errorf("step %s: on_error [%s] must be %s or %s", step.Name, step.OnError, batchStop, batchContinue)
+		}
+		// the variables may only use the results of earlier steps
+		for _, ref := range batchRefs(step) {
+			if err := checkBatchRef(&plan, names, ref); err != nil {
+				return nil, fmt.Errorf("step %s: %w", step.Name, err)
+			}
+		}
+		names[step.Name] = true
+	}
+	return &plan, nil
+}
+
+// batchRefs returns the variables used by the step
+func batchRefs(step *batchStep) []string {
+	var refs []string
+	var walk func(v any)
+	walk = func(v any) {
+		switch val := v.(type) {
+		case string:
+			for _, m := range batchVarRegexp.FindAllStringSubmatch(val, -1) {
+				refs = append(refs, m[1])
+			}
+		case map[string]any:
+			for _, item := range val {
+				walk(item)
+			}
+		case []any:
+			for _, item := range val {
+				walk(item)
+			}
+		}
+	}
+	walk(step.Contract)
+	walk(step.Expedite)
+	walk(step.Params)
+	return refs
+}
+
+func checkBatchRef(plan *batchPlan, earlier map[string]bool, ref string) error {
+	parts := strings.Split(ref, ".")
+	switch {
+	case len(parts) == 2 && parts[0] == "vars":
+		if _, ok := plan.Vars[parts[1]]; !ok {
+			return fmt.Errorf("${%s}: var %s isn't defined", ref, parts[1])
+		}
+		return nil
+	case len(parts) == 3 && parts[0] == "steps":
+		if !earlier[parts[1]] {
+			return fmt.Errorf("${%s}: %s isn't an earlier step", ref, parts[1])
+		}
+		switch parts[2] {
+		case "hash", "block_id", "result":
+			return nil
+		}
+		return fmt.Errorf("${%s}: %s must be hash, block_id or result", ref, parts[2])
+	}
+	return fmt.Errorf("${%s} unknown, ${vars.<name>} or ${steps.<name>.hash|block_id|result} expected", ref)
+}
+
+func batchFromStep(plan *batchPlan, from string) (int, error) {
+	if from == "" {
+		return 0, nil
+	}
+	for i, step := range plan.Steps {
+		if step.Name == from {
+			return i, nil
+		}
+	}
+	n, err := strconv.Atoi(from)
+	if err != nil || n < 1 || n > len(plan.Steps) {
+		return 0, fmt.Errorf("step %s not found", from)
+	}
+	return n - 1, nil
+}
+
+func batchOnError(plan *batchPlan, step *batchStep) string {
+	if step.OnError != "" {
+		return step.OnError
+	}
+	return plan.OnError
+}
+
+// expandBatchValue returns a copy of v with the variables replaced
+func expandBatchValue(plan *batchPlan, report *batchRunReport, v any) (any, error) {
+	switch val := v.(type) {
+	case string:
+		var expandErr error
+		s := batchVarRegexp.ReplaceAllStringFunc(val, func(m string) string {
+			value, err := batchVar(plan, report, m[2:len(m)-1])
+			if err != nil && expandErr == nil {
+				expandErr = err
+			}
+			return value
+		})
+		return s, expandErr
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			expanded, err := expandBatchValue(plan, report, item)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = expanded
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			expanded, err := expandBatchValue(plan, report, item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = expanded
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+func batchVar(plan *batchPlan, report *batchRunReport, ref string) (string, error) {
+	parts := strings.Split(ref, ".")
+	if parts[0] == "vars" {
+		return plan.Vars[parts[1]], nil
+	}
+	for _, rep := range report.Steps {
+		if rep.Name != parts[1] {
+			continue
+		}
+		if rep.Status != BatchStepOk {
+			return "", fmt.Errorf("${%s}: step %s is %s", ref, rep.Name, rep.Status)
+		}
+		switch parts[2] {
+		case "hash":
+			return rep.Hash, nil
+		case "block_id":
+			return strconv.FormatInt(rep.BlockId, 10), nil
+		}
+		return rep.Result, nil
+	}
+	return "", fmt.Errorf("${%s}: step %s not found", ref, parts[1])
+}
+
+// runBatchStep sends the call of the step and fills its report
+func runBatchStep(plan *batchPlan, report *batchRunReport, step *batchStep, rep *batchStepReport) error {
+	expanded, err := expandBatchValue(plan, report, []any{step.Contract, step.Expedite, step.Params})
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "variables invalid")
+	}
+	values := expanded.([]any)
+	contractName, expedite := values[0].(string), values[1].(string)
+	params, _ := values[2].(map[string]any)
+	if params == nil {
+		params = make(map[string]any)
+	}
+	if err = decodeParamTags(params); err != nil {
+		return clierr.Wrap(clierr.Argument, err, "Params invalid")
+	}
+	if expedite, err = parseExpedite(expedite); err != nil {
+		return clierr.Wrap(clierr.Argument, err, "Expedite invalid")
+	}
+
+	var result *response.TxStatusResult
+	if step.Utxo != "" {
+		utxoType, _ := parseUtxoType(step.Utxo)
+		utxoParams := request.MapParams(params)
+		if _, err = newUtxoTx(utxoType, utxoParams, expedite); err != nil {
+			return err
+		}
+		result, err = models.Client.AutoCallUtxo(utxoType, &utxoParams, expedite)
+		if err != nil {
+			return clierr.Wrap(clierr.RPC, err, "Call UTXO Failed")
+		}
+	} else {
+		rep.Contract = contractName
+		contract, err := models.Client.GetContract(contractName)
+		if err != nil {
+			return clierr.Wrap(clierr.RPC, err, "Get GetContract Failed")
+		}
+		if contract == nil {
+			return clierr.New(clierr.NotFound, "Get GetContract Result Empty")
+		}
+		converted, err := convertContractParams(contract.Fields, params)
+		if err != nil {
+			return clierr.Wrap(clierr.Argument, err, "Params invalid")
+		}
+		callParams := request.MapParams(converted)
+		result, err = models.Client.AutoCallContract(contract.Name, &callParams, expedite)
+		if err != nil {
+			return clierr.Wrap(clierr.RPC, err, "Call Contract Failed")
+		}
+	}
+	if result == nil {
+		return clierr.New(clierr.NotFound, "Call Result Empty")
+	}
+	rep.Hash, rep.BlockId, rep.Penalty = result.Hash, result.BlockId, result.Penalty
+	if result.Penalty == 1 || result.Err != "" {
+		return clierr.New(clierr.Rejected, "Call Rejected: %s", result.Err)
+	}
+	// the result of the contract is only given by the transaction status
+	if receipt, err := getTxReceipt(result.Hash); err == nil {
+		rep.Result = receipt.Result
+	} else {
+		log.Warnf("step %s: get the result failed: %s", step.Name, err)
+	}
+	return nil
+}
+
+func readBatchReport(filename string) (map[string]*batchStepReport, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var report batchRunReport
+	if err = json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+	steps := make(map[string]*batchStepReport, len(report.Steps))
+	for _, step := range report.Steps {
+		steps[step.Name] = step
+	}
+	return steps, nil
+}
+
+func writeBatchReport(filename string, report *batchRunReport) error {
+	data, err := json.MarshalIndent(report, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0644)
+}
@@ -0,0 +1,505 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/IBAX-io/go-ibax-sdk/packages/pkg/converter"
+	"github.com/IBAX-io/go-ibax-sdk/packages/request"
+	"github.com/IBAX-io/go-ibax-sdk/packages/response"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/IBAX-io/ibax-cli/models"
+	"github.com/IBAX-io/ibax-cli/packages/clierr"
+	"github.com/IBAX-io/ibax-cli/packages/parameter"
+	"github.com/spf13/cobra"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// blockPageSize is the most blocks the node returns in one request
+const blockPageSize = 100
+
+var (
+	blockWithTxs   bool
+	blockTxsFilter struct {
+		contract string
+		account  string
+		txType   string
+		since    string
+		until    string
+	}
+
+	blockCmd = &cobra.Command{
+		Use:   "block",
+		Short: "Explore the blocks and their transactions",
+		Long: `
+Times are ISO-8601 in UTC, key ids are account addresses and the node position of a block is resolved
+to the honor node of the honor_nodes platform parameter.
+`,
+	}
+
+	blockGetCmd = &cobra.Command{
+		Use:   "get [BlockId|Hash|latest]",
+		Short: "get a block with its transactions",
+		Long: `
+Request:
+	Block				(string) block id, block hash or latest
+` + blockViewDoc,
+		Args:       cobra.ExactArgs(1),
+		PreRunE:    loadConfigPre,
+		RunE:       blockGet,
+		SuggestFor: []string{"get"},
+		Example:    "./ibax-cli block get latest\n./ibax-cli block get 1000",
+	}
+
+	blockRangeCmd = &cobra.Command{
+		Use:   "range [Range]",
+		Short: "get the blocks of a range",
+		Long: `
+Request:
+	Range				(string) first..last, first.. or first..latest up to the latest block, or a single block id
+	--txs				(bool,optional) include the transactions of the blocks
+
+The blocks are requested 100 at a time, so a range can be of any size.
+Returns a json array of blocks, see "block get".
+`,
+		Args:       cobra.ExactArgs(1),
+		PreRunE:    loadConfigPre,
+		RunE:       blockRange,
+		SuggestFor: []string{"range"},
+		Example:    "./ibax-cli block range 1000..2000\n./ibax-cli block range 1000.. --txs -o table",
+	}
+
+	blockTxsCmd = &cobra.Command{
+		Use:   "txs [Range]",
+		Short: "list the transactions of a range of blocks",
+		Long: `
+Request:
+	Range				(string,optional) blocks like "block range", default the latest 100 blocks
+	--contract			(string,optional) contract name, with or without the ecosystem prefix: @1TokensSend || TokensSend
+	--account			(string,optional) signer, account address, @alias or key id
+	--type				(string,optional) transaction type: contract || utxo || transfer_self || delay || stop_network || first_block, or its number
+	--since				(string,optional) transactions from this time: 2023-04-14T02:31:51Z || 2023-04-14 || unix seconds || 24h (ago)
+	--until				(string,optional) transactions before this time, same formats as --since
+
+Returns a json array of transactions.
+Result:
+	[
+		{
+			"block_id": n,				(number) Block id
+			"hash": "str",				(string) Transaction hash
+			"contract_name": "str",		(string) Contract name
+			"params": {},				(object) Contract params or utxo params
+			"account": "str",			(string) Signer account address
+			"time": "str",				(string) Transaction time
+			"type": "str",				(string) Transaction type
+			"size": "str"				(string) Transaction size
+		}
+	]
+`,
+		Args:       cobra.RangeArgs(0, 1),
+		PreRunE:    loadConfigPre,
+		RunE:       blockTxs,
+		SuggestFor: []string{"txs"},
+		Example:    "./ibax-cli block txs 1000..2000 --contract=@1TokensSend --account=@treasury\n./ibax-cli block txs --type=utxo --since=1h",
+	}
+)
+
+const blockViewDoc = `
+Returns a json object of the block.
+Result:
+	{
+		"block_id": n,				(number) Block id
+		"hash": "str",				(string) Block hash
+		"time": "str",				(string) Block time
+		"account": "str",			(string) Account address of the node that signed the block
+		"node_position": n,			(number) Position of the node in the honor node list
+		"honor_node": {				(object) The honor node, null if it isn't in the honor_nodes parameter
+			"tcp_address": "str",	(string) TCP address
+			"api_address": "str",	(string) API address
+			"public_key": "str"		(string) Node public key
+		},
+		"version": n,				(number) Block version
+		"tx_count": n,				(number) Transaction count
+		"size": "str",				(string) Block size
+		"rollbacks_hash": "str",	(string) Rollbacks hash
+		"merkle_root": "str",		(string) Merkle root
+		"transactions": []			(array) Transactions, see "block txs"
+	}
+`
+
+func init() {
+	blockCmd.AddCommand(
+		blockGetCmd,
+		blockRangeCmd,
+		blockTxsCmd,
+	)
+	for _, subCommand := range blockCmd.Commands() {
+		for k, v := range subCommand.SuggestFor {
+			subCommand.SuggestFor[k] = blockCmd.Use + " " + v
+			models.AddWordsCompletions(subCommand.SuggestFor)
+		}
+	}
+	blockCmd.PersistentFlags().BoolVar(&noBlockCache, "no-cache", false, "request the blocks from the node instead of the block cache")
+	blockRangeCmd.Flags().BoolVar(&blockWithTxs, "txs", false, "include the transactions of the blocks")
+	blockTxsCmd.Flags().StringVar(&blockTxsFilter.contract, "contract", "", "contract name")
+	blockTxsCmd.Flags().StringVar(&blockTxsFilter.account, "account", "", "signer, account address, @alias or key id")
+	blockTxsCmd.Flags().StringVar(&blockTxsFilter.txType, "type", "", "transaction type, name or number")
+	blockTxsCmd.Flags().StringVar(&blockTxsFilter.since, "since", "", "transactions from this time")
+	blockTxsCmd.Flags().StringVar(&blockTxsFilter.until, "until", "", "transactions before this time")
+}
+
+type honorNodeView struct {
+	TCPAddress string `json:"tcp_address"`
+	APIAddress string `json:"api_address"`
+	PublicKey  string `json:"public_key"`
+}
+
+type blockView struct {
+	BlockId       int64          `json:"block_id"`
+	Hash          string         `json:"hash"`
+	Time          string         `json:"time"`
+	Account       string         `json:"account"`
+	NodePosition  int64          `json:"node_position"`
+	HonorNode     *honorNodeView `json:"honor_node"`
+	Version       int            `json:"version"`
+	TxCount       int32          `json:"tx_count"`
+	Size          string         `json:"size"`
+	RollbacksHash string         `json:"rollbacks_hash"`
+	MerkleRoot    string         `json:"merkle_root"`
+	Transactions  []*txView      `json:"transactions,omitempty"`
+}
+
+type txView struct {
+	BlockId      int64          `json:"block_id"`
+	Hash         string         `json:"hash"`
+	ContractName string         `json:"contract_name"`
+	Params       map[string]any `json:"params"`
+	Account      string         `json:"account"`
+	Time         string         `json:"time"`
+	Type         string         `json:"type"`
+	Size         string         `json:"size"`
+
+	keyId    int64
+	unixTime int64
+	txType   byte
+}
+
+var txTypeNames = map[byte]string{
+	types.FirstBlockTxType:    "first_block",
+	types.StopNetworkTxType:   "stop_network",
+	types.SmartContractTxType: "contract",
+	types.DelayTxType:         "delay",
+	types.UtxoTxType:          "utxo",
+	types.TransferSelfTxType:  "transfer_self",
+}
+
+func txTypeName(t byte) string {
+	if name, ok := txTypeNames[t]; ok {
+		return name
+	}
+	return strconv.Itoa(int(t))
+}
+
+func parseTxType(s string) (byte, error) {
+	for t, name := range txTypeNames {
+		if strings.EqualFold(s, name) {
+			return t, nil
+		}
+	}
+	t, err := strconv.ParseUint(s, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("transaction type [%s] unknown, contract || utxo || transfer_self || delay || stop_network || first_block", s)
+	}
+	return byte(t), nil
+}
+
+// isoTime formats a block or transaction time, transaction times may be in milliseconds
+func isoTime(t int64) string {
+	if t == 0 {
+		return ""
+	}
+	return unixTime(t).UTC().Format(time.RFC3339)
+}
+
+func unixTime(t int64) time.Time {
+	if t > 1e12 {
+		return time.UnixMilli(t)
+	}
+	return time.Unix(t, 0)
+}
+
+// honorNodes returns the honor nodes by node position, nil if the parameter can't be read
+func honorNodes() []*honorNodeView {
+	values, err := systemParamValues("honor_nodes")
+	if err != nil {
+		return nil
+	}
+	var nodes []*honorNodeView
+	if err = json.Unmarshal([]byte(values["honor_nodes"]), &nodes); err != nil {
+		return nil
+	}
+	return nodes
+}
+
+func newBlockView(info *response.BlockDetailedInfo, nodes []*honorNodeView, withTxs bool) *blockView {
+	b := &blockView{
+		BlockId:       info.Header.BlockId,
+		Hash:          info.Hash,
+		Time:          isoTime(info.Header.Time),
+		Account:       converter.AddressToString(info.Header.KeyId),
+		NodePosition:  info.Header.NodePosition,
+		Version:       info.Header.Version,
+		TxCount:       info.Tx,
+		Size:          info.Size,
+		RollbacksHash: info.RollbacksHash,
+		MerkleRoot:    info.MerkleRoot,
+	}
+	if b.NodePosition >= 0 && b.NodePosition < int64(len(nodes)) {
+		b.HonorNode = nodes[b.NodePosition]
+	}
+	if withTxs {
+		b.Transactions = newTxViews(info)
+	}
+	return b
+}
+
+func newTxViews(info *response.BlockDetailedInfo) []*txView {
+	list := make([]*txView, 0, len(info.Transactions))
+	for _, tx := range info.Transactions {
+		list = append(list, &txView{
+			BlockId:      info.Header.BlockId,
+			Hash:         tx.Hash,
+			ContractName: tx.ContractName,
+			Params:       tx.Params,
+			Account:      converter.AddressToString(tx.KeyID),
+			Time:         isoTime(tx.Time),
+			Type:         txTypeName(tx.Type),
+			Size:         tx.Size,
+			keyId:        tx.KeyID,
+			unixTime:     tx.Time,
+			txType:       tx.Type,
+		})
+	}
+	return list
+}
+
+func blockGet(cmd *cobra.Command, params []string) error {
+	args := parameter.New(params)
+	block, err := args.Set(0, true).String()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "block invalid")
+	}
+	var bh request.BlockIdOrHash
+	if block == "latest" {
+		bh.Id, err = models.Client.GetMaxBlockID()
+		if err != nil {
+			return clierr.Wrap(clierr.RPC, err, "Get Max Block Failed")
+		}
+	} else if id, err := strconv.ParseInt(block, 10, 64); err == nil {
+		bh.Id = id
+	} else {
+		bh.Hash = block
+	}
+	result, err := cachedBlock(bh)
+	if err != nil {
+		return clierr.Wrap(clierr.RPC, err, "detailed Block Failed")
+	}
+	if result == nil {
+		return clierr.New(clierr.NotFound, "detailed Block Result Empty")
+	}
+	return printResult(newBlockView(result, honorNodes(), true))
+}
+
+// parseBlockRange parses first..last, first.., first..latest or a single block id, latest is the max block
+func parseBlockRange(s string, maxBlock int64) (int64, int64, error) {
+	parse := func(v string) (int64, error) {
+		if v == "" || v == "latest" {
+			return maxBlock, nil
+		}
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id < 1 {
+			return 0, fmt.Errorf("block id [%s] invalid", v)
+		}
+		return id, nil
+	}
+	first, last, ok := strings.Cut(s, "..")
+	if !ok {
+		last = first
+	}
+	from, err := parse(first)
+	if err != nil {
+		return 0, 0, err
+	}
+	to, err := parse(last)
+	if err != nil {
+		return 0, 0, err
+	}
+	if to > maxBlock {
+		to = maxBlock
+	}
+	if from > to {
+		return 0, 0, fmt.Errorf("range [%s] is empty, the latest block is %d", s, maxBlock)
+	}
+	return from, to, nil
+}
+
+// fetchBlocks calls fn with the blocks from..to in order, requesting them a page at a time
+func fetchBlocks(from, to int64, fn func(*response.BlockDetailedInfo) error) error {
+	for start := from; start <= to; start += blockPageSize {
+		count := to - start + 1
+		if count > blockPageSize {
+			count = blockPageSize
+		}
+		// the block id is the first block of the page
+		result, err := models.DetailedBlocks(start, count)
+		if err != nil {
+			return fmt.Errorf("blocks %d..%d: %w", start, start+count-1, err)
+		}
+		if result == nil {
+			return fmt.Errorf("blocks %d..%d not found", start, start+count-1)
+		}
+		for id := start; id < start+count; id++ {
+			info, ok := (*result)[id]
+			if !ok {
+				return fmt.Errorf("block %d missing from blocks %d..%d", id, start, start+count-1)
+			}
+			if err = fn(&info); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func blockRange(cmd *cobra.Command, params []string) error {
+	args := parameter.New(params)
+	rangeStr, err := args.Set(0, true).String()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "Range invalid")
+	}
+	maxBlock, err := models.Client.GetMaxBlockID()
+	if err != nil {
+		return clierr.Wrap(clierr.RPC, err, "Get Max Block Failed")
+	}
+	from, to, err := parseBlockRange(rangeStr, maxBlock)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "Range invalid")
+	}
+	nodes := honorNodes()
+	list := make([]*blockView, 0, to-from+1)
+	err = scanBlocks(from, to, func(info *response.BlockDetailedInfo) error {
+		list = append(list, newBlockView(info, nodes, blockWithTxs))
+		return nil
+	})
+	if err != nil {
+		return clierr.Wrap(clierr.RPC, err, "Detailed Blocks Failed")
+	}
+	return printResult(list)
+}
+
+// parseTimeFilter parses RFC3339, a date, unix seconds or a duration before now
+func parseTimeFilter(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return unixTime(i), nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("time [%s] invalid, 2023-04-14T02:31:51Z || 2023-04-14 || unix seconds || 24h", s)
+}
+
+// txFilter returns whether a transaction matches the flags of block txs
+func txFilter() (func(*txView) bool, error) {
+	var checks []func(*txView) bool
+	f := blockTxsFilter
+	if f.contract != "" {
+		name := strings.ToLower(f.contract)
+		checks = append(checks, func(tx *txView) bool {
+			contract := strings.ToLower(tx.ContractName)
+			return contract == name || strings.HasPrefix(contract, "@") && strings.TrimLeft(contract[1:], "0123456789") == name
+		})
+	}
+	if f.account != "" {
+		keyId, err := parseKeyId(f.account)
+		if err != nil {
+			return nil, fmt.Errorf("account: %w", err)
+		}
+		checks = append(checks, func(tx *txView) bool { return tx.keyId == keyId })
+	}
+	if f.txType != "" {
+		t, err := parseTxType(f.txType)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, func(tx *txView) bool { return tx.txType == t })
+	}
+	if f.since != "" {
+		since, err := parseTimeFilter(f.since)
+		if err != nil {
+			return nil, fmt.Errorf("since: %w", err)
+		}
+		checks = append(checks, func(tx *txView) bool { return !unixTime(tx.unixTime).Before(since) })
+	}
+	if f.until != "" {
+		until, err := parseTimeFilter(f.until)
+		if err != nil {
+			return nil, fmt.Errorf("until: %w", err)
+		}
+		checks = append(checks, func(tx *txView) bool { return unixTime(tx.unixTime).Before(until) })
+	}
+	return func(tx *txView) bool {
+		for _, check := range checks {
+			if !check(tx) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func blockTxs(cmd *cobra.Command, params []string) error {
+	args := parameter.New(params)
+	rangeStr, err := args.Set(0, false).String()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "Range invalid")
+	}
+	match, err := txFilter()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "filter invalid")
+	}
+	maxBlock, err := models.Client.GetMaxBlockID()
+	if err != nil {
+		return clierr.Wrap(clierr.RPC, err, "Get Max Block Failed")
+	}
+	if rangeStr == "" {
+		first := maxBlock - blockPageSize + 1
+		if first < 1 {
+			first = 1
+		}
+		rangeStr = fmt.Sprintf("%d..", first)
+	}
+	from, to, err := parseBlockRange(rangeStr, maxBlock)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "Range invalid")
+	}
+	list := make([]*txView, 0)
+	err = scanBlocks(from, to, func(info *response.BlockDetailedInfo) error {
+		for _, tx := range newTxViews(info) {
+			if match(tx) {
+				list = append(list, tx)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return clierr.Wrap(clierr.RPC, err, "Detailed Blocks Failed")
+	}
+	return printResult(list)
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/IBAX-io/go-ibax-sdk/config"
+	"github.com/IBAX-io/go-ibax-sdk/packages/modus"
+	"github.com/IBAX-io/go-ibax-sdk/packages/response"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/IBAX-io/ibax-cli/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// utxoToKeyId is the recipient of the utxo transfer of every block of the fake node, it doesn't fit in a float64
+const utxoToKeyId = int64(1<<62 + 1)
+
+// fakeBlocksClient is a node answering ibax.detailedBlocks with the blocks 1..max,
+// the block id of the request is the first block
+type fakeBlocksClient struct {
+	modus.Client
+	max    int64
+	pages  [][2]int64
+	server *httptest.Server
+}
+
+func (c *fakeBlocksClient) GetConfig() *config.Config {
+	return &config.Config{ApiAddress: c.server.URL}
+}
+
+func (c *fakeBlocksClient) serve(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Method string  `json:"method"`
+		Params []int64 `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Method != "ibax.detailedBlocks" || len(req.Params) != 2 {
+		fmt.Fprint(w, `{"error":{"code":-32602,"message":"invalid request"}}`)
+		return
+	}
+	blockId, count := req.Params[0], req.Params[1]
+	if blockId <= 0 || count <= 0 {
+		fmt.Fprint(w, `{"error":{"code":-32602,"message":"invalid params"}}`)
+		return
+	}
+	c.pages = append(c.pages, [2]int64{blockId, count})
+	blocks := make([]string, 0, count)
+	for id := blockId; id < blockId+count && id <= c.max; id++ {
+		blocks = append(blocks, fmt.Sprintf(`"%d":{"header":{"block_id":%d},"hash":"%d","transactions":[`+
+			`{"hash":"t%d","type":%d,"params":{"UTXO":{"ToID":%d,"Value":"1","Comment":""}}}]}`,
+			id, id, id, id, types.UtxoTxType, utxoToKeyId))
+	}
+	fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":{%s}}`, strings.Join(blocks, ","))
+}
+
+func withFakeBlocks(t *testing.T, max int64) *fakeBlocksClient {
+	t.Helper()
+	client := models.Client
+	fake := &fakeBlocksClient{max: max}
+	fake.server = httptest.NewServer(http.HandlerFunc(fake.serve))
+	t.Cleanup(func() {
+		fake.server.Close()
+		models.Client = client
+	})
+	models.Client = fake
+	return fake
+}
+
+func TestParseBlockRange(t *testing.T) {
+	for _, c := range []struct {
+		in       string
+		from, to int64
+	}{
+		{"5", 5, 5},
+		{"1..10", 1, 10},
+		{"10..", 10, 500},
+		{"10..latest", 10, 500},
+		{"latest", 500, 500},
+		{"490..600", 490, 500},
+	} {
+		from, to, err := parseBlockRange(c.in, 500)
+		if err != nil || from != c.from || to != c.to {
+			t.Errorf("parseBlockRange(%q) = %d, %d, %v, want %d, %d", c.in, from, to, err, c.from, c.to)
+		}
+	}
+	for _, in := range []string{"0", "0..5", "10..5", "501..", "a..b", "-1"} {
+		if from, to, err := parseBlockRange(in, 500); err == nil {
+			t.Errorf("parseBlockRange(%q) = %d, %d, want an error", in, from, to)
+		}
+	}
+}
+
+func TestFetchBlocks(t *testing.T) {
+	fake := withFakeBlocks(t, 500)
+	var ids []int64
+	var views []*txView
+	err := fetchBlocks(1, 250, func(info *response.BlockDetailedInfo) error {
+		ids = append(ids, info.Header.BlockId)
+		views = append(views, newTxViews(info)...)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(views) != 250 {
+		t.Fatalf("%d transactions, want 250", len(views))
+	}
+	for _, tx := range views {
+		if to, ok := utxoToId(tx.Params); !ok || to != utxoToKeyId {
+			t.Fatalf("ToID of %s = %d, %v, want %d", tx.Hash, to, ok, utxoToKeyId)
+		}
+		if historyDirection(tx, utxoToKeyId) != "in" || historyDirection(tx, utxoToKeyId-1) != "" {
+			t.Fatalf("direction of %s isn't in for key id %d only", tx.Hash, utxoToKeyId)
+		}
+	}
+	if len(ids) != 250 {
+		t.Fatalf("%d blocks, want 250", len(ids))
+	}
+	for i, id := range ids {
+		if id != int64(i+1) {
+			t.Fatalf("block %d is %d, want %d", i, id, i+1)
+		}
+	}
+	want := [][2]int64{{1, 100}, {101, 100}, {201, 50}}
+	if len(fake.pages) != len(want) {
+		t.Fatalf("pages %v, want %v", fake.pages, want)
+	}
+	for i := range want {
+		if fake.pages[i] != want[i] {
+			t.Errorf("page %d = %v, want %v", i, fake.pages[i], want[i])
+		}
+	}
+}
+
+func TestFetchBlocksMissing(t *testing.T) {
+	withFakeBlocks(t, 120)
+	var last int64
+	err := fetchBlocks(90, 130, func(info *response.BlockDetailedInfo) error {
+		last = info.Header.BlockId
+		return nil
+	})
+	if err == nil {
+		t.Fatal("missing blocks not reported")
+	}
+	if last != 120 {
+		t.Errorf("last block %d, want 120", last)
+	}
+}
@@ -0,0 +1,609 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/IBAX-io/ibax-cli/models"
+	"github.com/IBAX-io/ibax-cli/packages/clierr"
+	"github.com/IBAX-io/ibax-cli/packages/money"
+	"github.com/IBAX-io/ibax-cli/packages/parameter"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	bulkWorkers  int
+	bulkRate     float64
+	bulkState    string
+	bulkResult   string
+	bulkExpedite string
+	bulkTimeout  time.Duration
+
+	utxoBulkTransferCmd = &cobra.Command{
+		Use:   "bulk-transfer [CsvFile]",
+		Short: "transfer to the recipients of a csv file",
+		Long: `
+Request:
+	CsvFile				(string) csv file with the columns recipient, amount and comment
+		recipient		(string) recipient address: "xxxx-xxxx-xxxx-xxxx-xxxx", @alias of the address book or key id
+		amount			(string) amount, smallest unit or with the unit of the token: "1.5IBAX"
+		comment			(string,optional) transaction comment
+	A first row naming the columns is optional, it sets their order.
+
+Every row is checked before anything is sent, and the total of the amounts against the UTXO balance.
+The estimated fee of every row, fuel_rate / 10 per byte and the expedite, is added to the total when the
+ecosystem is 1, otherwise it is checked against the UTXO balance of IBAX. The fuel of the spent outputs
+isn't estimated, so a balance close to the total may still run out.
+The transfers are signed in order, each with its own time, and sent by --workers at most --rate per second.
+
+The state of every row is appended to --state. Running the command again with the same files resumes:
+committed rows are skipped, sent rows are checked on the node before they are sent again with the same hash,
+so no row is paid twice. Rejected rows and rows that failed to be sent are sent again.
+The result of every row is written to --result.
+
+Returns a json object of the totals.
+Result:
+	{
+		"rows": n,				(number) Rows of the csv file
+		"committed": n,			(number) Rows in a block
+		"failed": n,			(number) Rows rejected, penalized or not sent
+		"amount": "str",		(string) Total amount of the committed rows
+		"state": "str",			(string) State file
+		"result": "str"			(string) Result csv file
+	}
+`,
+		Args:       cobra.ExactArgs(1),
+		PreRunE:    loginPre,
+		RunE:       utxoBulkTransfer,
+		SuggestFor: []string{"bulk-transfer"},
+		Example:    "./ibax-cli utxo bulk-transfer payouts.csv --workers=8 --rate=20",
+	}
+)
+
+const (
+	BulkSigned    = "signed"
+	BulkSent      = "sent"
+	BulkSendError = "send_failed"
+	BulkTimeout   = "timeout"
+)
+
+// bulkRow is a transfer of the csv file, Row counts from 1 without the header
+type bulkRow struct {
+	Row       int
+	Recipient string
+	ToId      int64
+	Amount    decimal.Decimal
+	Comment   string
+}
+
+// bulkRowState is a line of the state file, the last line of a row is its state
+type bulkRowState struct {
+	Row     int    `json:"row"`
+	Time    int64  `json:"time"`
+	Hash    string `json:"hash"`
+	Status  string `json:"status"`
+	BlockId int64  `json:"block_id,omitempty"`
+	Err     string `json:"err,omitempty"`
+}
+
+type bulkSummary struct {
+	Rows      int    `json:"rows"`
+	Committed int    `json:"committed"`
+	Failed    int    `json:"failed"`
+	Amount    string `json:"amount"`
+	State     string `json:"state"`
+	Result    string `json:"result"`
+}
+
+// bulkJob is a signed transfer to send
+type bulkJob struct {
+	row  *bulkRow
+	hash string
+	data []byte
+	// resend is set if the transaction may be on the node already
+	resend bool
+}
+
+func utxoBulkTransfer(cmd *cobra.Command, params []string) error {
+	args := parameter.New(params)
+	csvFile, err := args.Set(0, true).String()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "CsvFile invalid")
+	}
+	if bulkWorkers < 1 {
+		return clierr.New(clierr.Argument, "workers must be at least 1")
+	}
+	if bulkRate < 0 {
+		return clierr.New(clierr.Argument, "rate can't be negative")
+	}
+	expedite, err := parseExpedite(bulkExpedite)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "Expedite invalid")
+	}
+	base := strings.TrimSuffix(csvFile, ".csv")
+	stateFile, resultFile := bulkState, bulkResult
+	if stateFile == "" {
+		stateFile = base + ".state"
+	}
+	if resultFile == "" {
+		resultFile = base + ".result.csv"
+	}
+
+	cnf := models.Client.GetConfig()
+	balance, err := models.Client.Balance(cnf.Account, cnf.Ecosystem)
+	if err != nil {
+		return clierr.Wrap(clierr.RPC, err, "Get Balance Failed")
+	}
+	if balance == nil {
+		return clierr.New(clierr.NotFound, "Get Balance Result Empty")
+	}
+	token := money.Token{Symbol: balance.TokenSymbol, Digits: int32(balance.Digits)}
+	rows, err := readBulkRows(csvFile, token)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "csv file invalid")
+	}
+	states, err := readBulkStates(stateFile)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "state file invalid")
+	}
+	for row := range states {
+		if row > len(rows) {
+			return clierr.New(clierr.Argument, "state file %s has row %d, the csv file has %d rows", stateFile, row, len(rows))
+		}
+	}
+
+	rate, err := utxoFuelRate()
+	if err != nil {
+		return clierr.Wrap(clierr.RPC, err, "Get Fuel Rate Failed")
+	}
+	expediteFee, err := expediteAmount(expedite)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "Expedite invalid")
+	}
+	total, fees, err := bulkTotal(rows, states, expedite, rate, expediteFee)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "rows invalid")
+	}
+	utxo, err := decimal.NewFromString(balance.Utxo)
+	if err != nil {
+		return clierr.Wrap(clierr.RPC, err, "UTXO balance invalid")
+	}
+	// the fees are paid from the UTXO of ecosystem 1, with the amounts when they are IBAX
+	feeUtxo := utxo
+	if cnf.Ecosystem == consts.DefaultTokenEcosystem {
+		total = total.Add(fees)
+	} else {
+		feeBalance, err := models.Client.Balance(cnf.Account, consts.DefaultTokenEcosystem)
+		if err != nil {
+			return clierr.Wrap(clierr.RPC, err, "Get Balance Failed")
+		}
+		if feeBalance == nil {
+			return clierr.New(clierr.NotFound, "Get Balance Result Empty")
+		}
+		if feeUtxo, err = decimal.NewFromString(feeBalance.Utxo); err != nil {
+			return clierr.Wrap(clierr.RPC, err, "UTXO balance invalid")
+		}
+	}
+	if total.GreaterThan(utxo) {
+		return clierr.New(clierr.Argument, "total %s of the rows to send, with the estimated fees in IBAX, exceeds the UTXO balance %s of %s",
+			token.Format(total.String()), token.Format(utxo.String()), cnf.Account)
+	}
+	if cnf.Ecosystem != consts.DefaultTokenEcosystem && fees.GreaterThan(feeUtxo) {
+		ibax := money.Token{Symbol: "IBAX", Digits: consts.MoneyDigits}
+		return clierr.New(clierr.Argument, "estimated fees %s of the rows to send exceed the UTXO balance %s of %s",
+			ibax.Format(fees.String()), ibax.Format(feeUtxo.String()), cnf.Account)
+	}
+
+	privateKey, err := configPrivateKey()
+	if err != nil {
+		return err
+	}
+	networkId, err := nodeNetworkId()
+	if err != nil {
+		return clierr.Wrap(clierr.RPC, err, "Get Network Failed")
+	}
+	stateOut, err := os.OpenFile(stateFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return clierr.Wrap(clierr.Unknown, err, "open state file failed")
+	}
+	defer stateOut.Close()
+	// OpenFile keeps the mode of an existing file, the states hold the hashes of the account's transfers
+	if err = stateOut.Chmod(0600); err != nil {
+		return clierr.Wrap(clierr.Unknown, err, "open state file failed")
+	}
+
+	run := &bulkRun{expedite: expedite, states: states, out: stateOut, progress: newProgress(len(rows))}
+	for _, state := range states {
+		switch state.Status {
+		case TxCommitted:
+			run.progress.add(true)
+		case TxPenalized:
+			run.progress.add(false)
+		}
+	}
+	jobs := make(chan *bulkJob, bulkWorkers)
+	var wg sync.WaitGroup
+	for i := 0; i < bulkWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				run.send(job)
+			}
+		}()
+	}
+	signErr := withAlgorithm(configAlgorithm(), func() error {
+		defer close(jobs)
+		return run.sign(rows, privateKey, networkId, jobs)
+	})
+	wg.Wait()
+	run.progress.done()
+	if signErr != nil {
+		return clierr.Wrap(clierr.Auth, signErr, "sign transaction failed")
+	}
+	if run.writeErr != nil {
+		return clierr.Wrap(clierr.Unknown, run.writeErr, "save state failed")
+	}
+
+	summary := bulkSummary{Rows: len(rows), State: stateFile, Result: resultFile}
+	amount := decimal.Zero
+	for _, row := range rows {
+		if state, ok := run.states[row.Row]; ok && state.Status == TxCommitted {
+			summary.Committed++
+			amount = amount.Add(row.Amount)
+		} else {
+			summary.Failed++
+		}
+	}
+	summary.Amount = amount.String()
+	if err = writeBulkResult(resultFile, rows, run.states); err != nil {
+		return clierr.Wrap(clierr.Unknown, err, "save result failed")
+	}
+	if err = printResult(summary); err != nil {
+		return err
+	}
+	if summary.Failed > 0 {
+		return clierr.New(clierr.Rejected, "%d of %d transfers failed, see %s", summary.Failed, summary.Rows, resultFile)
+	}
+	return nil
+}
+
+type bulkRun struct {
+	expedite string
+	mu       sync.Mutex
+	states   map[int]*bulkRowState
+	out      io.Writer
+	writeErr error
+	progress *progress
+}
+
+// bulkTx returns the transfer of the row, the header is set by the sender
+func bulkTx(row *bulkRow, expedite string) *types.SmartTransaction {
+	return &types.SmartTransaction{
+		Header:   &types.Header{},
+		Expedite: expedite,
+		UTXO:     &types.UTXO{ToID: row.ToId, Value: row.Amount.String(), Comment: row.Comment},
+	}
+}
+
+// bulkTotal returns the amount and the estimated fees of the rows that aren't in a block yet
+func bulkTotal(rows []*bulkRow, states map[int]*bulkRowState, expedite string, rate, expediteFee decimal.Decimal) (total, fees decimal.Decimal, err error) {
+	now := time.Now().Unix()
+	for _, row := range rows {
+		if bulkRowAction(states[row.Row], now) == bulkSkip {
+			continue
+		}
+		total = total.Add(row.Amount)
+		result, err := newDryRun(bulkTx(row, expedite), nil)
+		if err != nil {
+			return total, fees, fmt.Errorf("row %d: %w", row.Row, err)
+		}
+		sizeFee, expediteFee := utxoTxFee(rate, result.TxSize, expediteFee)
+		fees = fees.Add(sizeFee).Add(expediteFee)
+	}
+	return total, fees, nil
+}
+
+// bulkAction is what a resumed run does with a row
+type bulkAction int
+
+const (
+	// bulkSign signs the row with a new time
+	bulkSign bulkAction = iota
+	// bulkSkip skips the row, it is in a block
+	bulkSkip
+	// bulkResend sends the row again with the time of its state, so that it keeps its hash
+	bulkResend
+	// bulkCheck signs the row again unless the node has it in a block, it can't get into one anymore
+	bulkCheck
+)
+
+// bulkRowAction returns what to do with the row of the state at the time now, the state is nil for a new row
+func bulkRowAction(state *bulkRowState, now int64) bulkAction {
+	if state == nil {
+		return bulkSign
+	}
+	switch state.Status {
+	case TxCommitted, TxPenalized:
+		return bulkSkip
+	case BulkSigned, BulkSent, BulkTimeout, TxPending:
+		if state.Time > now-consts.MaxTxBack+60 {
+			return bulkResend
+		}
+		return bulkCheck
+	}
+	return bulkSign
+}
+
+// sign signs the rows to send in order, a row sent before keeps its time so it keeps its hash
+func (r *bulkRun) sign(rows []*bulkRow, privateKey []byte, networkId int64, jobs chan<- *bulkJob) error {
+	var limit <-chan time.Time
+	if bulkRate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / bulkRate))
+		defer ticker.Stop()
+		limit = ticker.C
+	}
+	// transfers of the same recipient, amount and comment get different times, otherwise their hash is the same
+	lastTime := make(map[string]int64)
+	for _, row := range rows {
+		state := r.state(row.Row)
+		txTime, resend := time.Now().Unix(), false
+		switch bulkRowAction(state, txTime) {
+		case bulkSkip:
+			continue
+		case bulkResend:
+			txTime, resend = state.Time, true
+		case bulkCheck:
+			if receipt, err := getTxReceipt(state.Hash); err == nil && receipt.final() {
+				r.finish(*state, receipt, nil)
+				continue
+			}
+		}
+		key := fmt.Sprintf("%d/%s/%s", row.ToId, row.Amount, row.Comment)
+		if !resend && lastTime[key] >= txTime {
+			txTime = lastTime[key] + 1
+		}
+		lastTime[key] = txTime
+
+		hash, data, err := signTx(bulkTx(row, r.expedite), privateKey, networkId, txTime)
+		if err != nil {
+			return fmt.Errorf("row %d: %w", row.Row, err)
+		}
+		if resend && hash != state.Hash {
+			// the status is kept, so that the row isn't signed again with a new time
+			changed := *state
+			changed.Err = "the row has changed since it was signed, check the transaction hash before removing its state"
+			r.record(&changed)
+			r.progress.add(false)
+			continue
+		}
+		if !resend {
+			// the hash is saved before sending, so that a resumed run finds the transaction
+			r.record(&bulkRowState{Row: row.Row, Time: txTime, Hash: hash, Status: BulkSigned})
+		}
+		if limit != nil {
+			<-limit
+		}
+		jobs <- &bulkJob{row: row, hash: hash, data: data, resend: resend}
+	}
+	return nil
+}
+
+// send sends the transfer and waits for its block
+func (r *bulkRun) send(job *bulkJob) {
+	state := *r.state(job.row.Row)
+	if job.resend {
+		if receipt, err := getTxReceipt(job.hash); err == nil && receipt.final() {
+			r.finish(state, receipt, nil)
+			return
+		}
+	}
+	if err := sendTxData(job.hash, job.data); err != nil && !job.resend {
+		state.Status, state.Err = BulkSendError, err.Error()
+		r.record(&state)
+		r.progress.add(false)
+		return
+	}
+	state.Status = BulkSent
+	r.record(&state)
+	receipt, err := waitTxReceipt(job.hash, bulkTimeout, 1)
+	r.finish(state, receipt, err)
+}
+
+func (r *bulkRun) finish(state bulkRowState, receipt *txReceipt, err error) {
+	if err != nil {
+		state.Status, state.Err = BulkTimeout, err.Error()
+		r.record(&state)
+		r.progress.add(false)
+		return
+	}
+	state.Status, state.BlockId, state.Err = receipt.Status, receipt.BlockId, receipt.Err
+	r.record(&state)
+	r.progress.add(receipt.Status == TxCommitted)
+}
+
+func (r *bulkRun) state(row int) *bulkRowState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.states[row]
+}
+
+// record sets the state of the row and appends it to the state file
+func (r *bulkRun) record(state *bulkRowState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.states[state.Row] = state
+	data, err := json.Marshal(state)
+	if err == nil {
+		_, err = r.out.Write(append(data, '\n'))
+	}
+	if err != nil && r.writeErr == nil {
+		r.writeErr = err
+	}
+}
+
+// readBulkRows reads and checks every row of the csv file, all problems are reported together
+func readBulkRows(filename string, token money.Token) ([]*bulkRow, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	columns := map[string]int{"recipient": 0, "amount": 1, "comment": 2}
+	header := make(map[string]int)
+	if len(records) > 0 {
+		for i, name := range records[0] {
+			header[strings.ToLower(strings.TrimSpace(name))] = i
+		}
+	}
+	if _, ok := header["recipient"]; ok {
+		columns = header
+		records = records[1:]
+		for _, name := range []string{"recipient", "amount"} {
+			if _, ok := columns[name]; !ok {
+				return nil, fmt.Errorf("column %s is missing", name)
+			}
+		}
+	}
+	if len(records) == 0 {
+		return nil, errors.New("no rows")
+	}
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+	var problems []error
+	rows := make([]*bulkRow, 0, len(records))
+	for i, record := range records {
+		row := &bulkRow{Row: i + 1, Recipient: field(record, "recipient"), Comment: field(record, "comment")}
+		row.ToId, err = parseKeyId(row.Recipient)
+		if err != nil || row.ToId == 0 {
+			problems = append(problems, fmt.Errorf("row %d: recipient [%s] isn't an account address, @alias or key id", row.Row, row.Recipient))
+		}
+		amount := field(record, "amount")
+		row.Amount, err = token.Smallest(amount)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("row %d: %w", row.Row, err))
+		} else if !row.Amount.IsPositive() {
+			problems = append(problems, fmt.Errorf("row %d: amount [%s] must be positive", row.Row, amount))
+		}
+		rows = append(rows, row)
+	}
+	if len(problems) > 0 {
+		return nil, errors.Join(problems...)
+	}
+	return rows, nil
+}
+
+func readBulkStates(filename string) (map[int]*bulkRowState, error) {
+	states := make(map[int]*bulkRowState)
+	f, err := os.Open(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return states, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		state := &bulkRowState{}
+		if err := json.Unmarshal(scanner.Bytes(), state); err != nil {
+			// the last line may be cut by a crash
+			continue
+		}
+		states[state.Row] = state
+	}
+	return states, scanner.Err()
+}
+
+func writeBulkResult(filename string, rows []*bulkRow, states map[int]*bulkRowState) error {
+	f, err := os.OpenFile(filename, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err = f.Chmod(0600); err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	w.Write([]string{"row", "recipient", "amount", "comment", "status", "hash", "block_id", "error"})
+	for _, row := range rows {
+		record := []string{strconv.Itoa(row.Row), row.Recipient, row.Amount.String(), row.Comment, "not_sent", "", "", ""}
+		if state, ok := states[row.Row]; ok {
+			record[4], record[5], record[7] = state.Status, state.Hash, state.Err
+			if state.BlockId > 0 {
+				record[6] = strconv.FormatInt(state.BlockId, 10)
+			}
+		}
+		w.Write(record)
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// progress draws a progress bar on stderr
+type progress struct {
+	mu     sync.Mutex
+	total  int
+	ok     int
+	failed int
+}
+
+func newProgress(total int) *progress {
+	return &progress{total: total}
+}
+
+func (p *progress) add(ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if ok {
+		p.ok++
+	} else {
+		p.failed++
+	}
+	p.draw()
+}
+
+func (p *progress) draw() {
+	const width = 40
+	n := p.ok + p.failed
+	filled := 0
+	if p.total > 0 {
+		filled = n * width / p.total
+	}
+	fmt.Fprintf(os.Stderr, "\r[%s%s] %d/%d committed %d failed %d", strings.Repeat("=", filled), strings.Repeat(" ", width-filled), n, p.total, p.ok, p.failed)
+}
+
+func (p *progress) done() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.draw()
+	fmt.Fprintln(os.Stderr)
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"github.com/IBAX-io/go-ibax-sdk/config"
+	"github.com/IBAX-io/go-ibax-sdk/packages/modus"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/ibax-cli/models"
+	"github.com/IBAX-io/ibax-cli/packages/money"
+	"github.com/shopspring/decimal"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var bulkToken = money.Token{Symbol: "IBAX", Digits: 12}
+
+func writeBulkFile(t *testing.T, name, data string) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(filename, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestReadBulkRows(t *testing.T) {
+	for _, c := range []struct {
+		name, data string
+	}{
+		{"without header", "1001,1000,first\n1002, 2.5IBAX\n"},
+		{"header", "recipient,amount,comment\n1001,1000,first\n1002,2.5IBAX,\n"},
+		{"header order", "comment,amount,recipient\nfirst,1000,1001\n,2.5IBAX,1002\n"},
+	} {
+		rows, err := readBulkRows(writeBulkFile(t, "rows.csv", c.data), bulkToken)
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		if len(rows) != 2 {
+			t.Errorf("%s: %d rows, want 2", c.name, len(rows))
+			continue
+		}
+		first, second := rows[0], rows[1]
+		if first.Row != 1 || first.ToId != 1001 || first.Amount.String() != "1000" || first.Comment != "first" {
+			t.Errorf("%s: row 1 = %+v", c.name, first)
+		}
+		if second.Row != 2 || second.ToId != 1002 || second.Amount.String() != "2500000000000" || second.Comment != "" {
+			t.Errorf("%s: row 2 = %+v", c.name, second)
+		}
+	}
+
+	_, err := readBulkRows(writeBulkFile(t, "bad.csv", "abc,1000\n1002,1.5\n1003,-5\n1004,1ETH\n1005,0\n"), bulkToken)
+	if err == nil {
+		t.Fatal("invalid rows accepted")
+	}
+	for _, want := range []string{"row 1: recipient [abc]", "row 2:", "row 3:", "row 4:", "row 5: amount [0] must be positive"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("err = %v, want %q", err, want)
+		}
+	}
+	for _, data := range []string{"", "recipient,amount\n", "recipient,comment\n1001,x\n"} {
+		if _, err = readBulkRows(writeBulkFile(t, "empty.csv", data), bulkToken); err == nil {
+			t.Errorf("rows %q accepted", data)
+		}
+	}
+}
+
+func TestReadBulkStates(t *testing.T) {
+	states, err := readBulkStates(filepath.Join(t.TempDir(), "missing.state"))
+	if err != nil || len(states) != 0 {
+		t.Fatalf("missing state file = %v, %v, want no states", states, err)
+	}
+	// the last line of a row is its state, a line cut by a crash is skipped
+	data := `{"row":1,"time":100,"hash":"h1","status":"signed"}
+{"row":2,"time":100,"hash":"h2","status":"signed"}
+
+{"row":1,"time":100,"hash":"h1","status":"committed","block_id":7}
+{"row":2,"time":100,"hash":"h2","sta`
+	states, err = readBulkStates(writeBulkFile(t, "rows.state", data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 2 {
+		t.Fatalf("%d states, want 2", len(states))
+	}
+	if s := states[1]; s.Status != TxCommitted || s.BlockId != 7 {
+		t.Errorf("row 1 = %+v, want committed in block 7", s)
+	}
+	if s := states[2]; s.Status != BulkSigned || s.Hash != "h2" {
+		t.Errorf("row 2 = %+v, want signed", s)
+	}
+}
+
+func TestBulkRowAction(t *testing.T) {
+	const now = 1_700_000_000
+	recent, expired := int64(now-60), int64(now-consts.MaxTxBack)
+	for _, c := range []struct {
+		state *bulkRowState
+		want  bulkAction
+	}{
+		{nil, bulkSign},
+		{&bulkRowState{Status: TxCommitted, Time: recent}, bulkSkip},
+		{&bulkRowState{Status: TxPenalized, Time: expired}, bulkSkip},
+		{&bulkRowState{Status: BulkSigned, Time: recent}, bulkResend},
+		{&bulkRowState{Status: BulkSent, Time: recent}, bulkResend},
+		{&bulkRowState{Status: BulkTimeout, Time: recent}, bulkResend},
+		{&bulkRowState{Status: TxPending, Time: recent}, bulkResend},
+		{&bulkRowState{Status: BulkSent, Time: expired}, bulkCheck},
+		{&bulkRowState{Status: BulkSendError, Time: recent}, bulkSign},
+		{&bulkRowState{Status: TxRejected, Time: recent}, bulkSign},
+	} {
+		if got := bulkRowAction(c.state, now); got != c.want {
+			t.Errorf("bulkRowAction(%+v) = %d, want %d", c.state, got, c.want)
+		}
+	}
+}
+
+// fakeConfigClient is a client of the config only
+type fakeConfigClient struct {
+	modus.Client
+	cnf config.Config
+}
+
+func (c *fakeConfigClient) GetConfig() *config.Config {
+	return &c.cnf
+}
+
+func TestBulkTotal(t *testing.T) {
+	client := models.Client
+	defer func() { models.Client = client }()
+	models.Client = &fakeConfigClient{cnf: config.Config{Ecosystem: 1, KeyId: 1001}}
+
+	rows := []*bulkRow{
+		{Row: 1, ToId: 1002, Amount: decimal.NewFromInt(1000)},
+		{Row: 2, ToId: 1003, Amount: decimal.NewFromInt(2000), Comment: "second"},
+		{Row: 3, ToId: 1004, Amount: decimal.NewFromInt(4000)},
+	}
+	states := map[int]*bulkRowState{1: {Row: 1, Status: TxCommitted}, 3: {Row: 3, Status: TxRejected}}
+	rate, expediteFee := decimal.NewFromInt(100), decimal.RequireFromString("0.5")
+	total, fees, err := bulkTotal(rows, states, "0.5", rate, expediteFee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !total.Equal(decimal.NewFromInt(6000)) {
+		t.Errorf("total = %s, want 6000 of the rows 2 and 3", total)
+	}
+	want := decimal.Zero
+	for _, row := range rows[1:] {
+		result, err := newDryRun(bulkTx(row, "0.5"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// fuel_rate / 10 per byte and the expedite in the smallest unit of IBAX
+		want = want.Add(decimal.NewFromInt(int64(10 * result.TxSize))).Add(decimal.New(5, 11))
+	}
+	if !fees.Equal(want) {
+		t.Errorf("fees = %s, want %s", fees, want)
+	}
+}
@@ -0,0 +1,544 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/IBAX-io/go-ibax-sdk/packages/modus"
+	"github.com/IBAX-io/go-ibax-sdk/packages/request"
+	"github.com/IBAX-io/go-ibax-sdk/packages/response"
+	"github.com/IBAX-io/ibax-cli/conf"
+	"github.com/IBAX-io/ibax-cli/models"
+	"github.com/IBAX-io/ibax-cli/packages/blockcache"
+	"github.com/IBAX-io/ibax-cli/packages/clierr"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// blockCacheDepth is the number of the latest blocks that aren't cached, they may still be rolled back
+	blockCacheDepth = 10
+	// blockCacheOff is the block_cache setting disabling the cache
+	blockCacheOff = "off"
+)
+
+var (
+	noBlockCache     bool
+	cachePruneBefore int64
+	cachePruneKeep   int64
+	cachePruneAll    bool
+
+	cacheCmd = &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the local block cache",
+		Long: `
+The blocks fetched from the node are kept in blocks-<network id>.db in the data directory, or in the file
+of the block_cache setting of config.yml; block_cache: off disables the cache.
+The block commands, account history, detailedBlock, detailedBlocks, blocksTxInfo and getBlockInfo read the cached
+blocks instead of requesting them. The latest 10 blocks aren't cached since they may still be rolled back.
+A block is stored with the hash of the detailedBlocks response and read by its id only while its hash is indexed
+to it, getBlockInfo of a cached block is requested once and removes the block when the hashes differ.
+The cached blocks aren't requested again, cache rebuild checks them against the node.
+`,
+	}
+
+	cacheStatsCmd = &cobra.Command{
+		Use:   "stats",
+		Short: "show the size of the cache",
+		Long: `
+Request:
+	No parameters required
+
+Returns a json object of the cache.
+Result:
+	{
+		"path": "str",				(string) cache file
+		"network_id": "str",		(string) network of the blocks
+		"blocks": n,				(number) cached blocks
+		"transactions": n,			(number) transactions of the cached blocks
+		"infos": n,					(number) cached results of getBlockInfo
+		"first_block": n,			(number) first cached block
+		"last_block": n,			(number) last cached block
+		"size": n					(number) file size in bytes
+	}
+`,
+		Args:       cobra.NoArgs,
+		PreRunE:    loadConfigPre,
+		RunE:       cacheStats,
+		SuggestFor: []string{"stats"},
+		Example:    "./ibax-cli cache stats",
+	}
+
+	cachePruneCmd = &cobra.Command{
+		Use:   "prune",
+		Short: "remove cached blocks",
+		Long: `
+Request:
+	--before		(number,optional) remove the blocks before this block
+	--keep			(number,optional) keep the latest cached blocks
+	--all			(bool,optional) remove all blocks
+
+One of the flags is required.
+Result:
+	{
+		"removed": n,		(number) removed blocks and block infos
+		"blocks": n			(number) blocks left
+	}
+`,
+		Args:       cobra.NoArgs,
+		PreRunE:    loadConfigPre,
+		RunE:       cachePrune,
+		SuggestFor: []string{"prune"},
+		Example:    "./ibax-cli cache prune --before=100000\n./ibax-cli cache prune --keep=50000",
+	}
+
+	cacheRebuildCmd = &cobra.Command{
+		Use:   "rebuild",
+		Short: "request the cached blocks again and replace the changed ones",
+		Long: `
+Request:
+	No parameters required
+
+Requests the cached blocks from the node a page at a time and compares their hashes, changed blocks are
+replaced and blocks after the max block of the node are removed. The cached block infos are checked against
+the hashes of the blocks, a block info of another hash is removed and requested again by getBlockInfo.
+Result:
+	{
+		"blocks": n,			(number) checked blocks
+		"replaced": n,			(number) replaced blocks
+		"removed": n,			(number) removed blocks
+		"infos": n,				(number) checked block infos
+		"infos_removed": n		(number) removed block infos
+	}
+`,
+		Args:       cobra.NoArgs,
+		PreRunE:    loadConfigPre,
+		RunE:       cacheRebuild,
+		SuggestFor: []string{"rebuild"},
+		Example:    "./ibax-cli cache rebuild",
+	}
+)
+
+func init() {
+	cacheCmd.AddCommand(
+		cacheStatsCmd,
+		cachePruneCmd,
+		cacheRebuildCmd,
+	)
+	for _, subCommand := range cacheCmd.Commands() {
+		for k, v := range subCommand.SuggestFor {
+			subCommand.SuggestFor[k] = cacheCmd.Use + " " + v
+			models.AddWordsCompletions(subCommand.SuggestFor)
+		}
+	}
+	cachePruneCmd.Flags().Int64Var(&cachePruneBefore, "before", 0, "remove the blocks before this block")
+	cachePruneCmd.Flags().Int64Var(&cachePruneKeep, "keep", 0, "keep the latest cached blocks")
+	cachePruneCmd.Flags().BoolVar(&cachePruneAll, "all", false, "remove all blocks")
+}
+
+// blockCacheState is the block cache of the client, opened by the first block request
+var blockCacheState struct {
+	client  modus.Client
+	cache   *blockcache.Cache
+	cacheTo int64 // the blocks up to cacheTo are old enough to be cached
+}
+
+// openBlockCacheFile opens the cache of the network of the node
+func openBlockCacheFile() (*blockcache.Cache, error) {
+	if conf.Config.BlockCache == blockCacheOff {
+		return nil, fmt.Errorf("block_cache is %s in %s", blockCacheOff, conf.Config.ConfigPath)
+	}
+	networkId, err := nodeNetworkId()
+	if err != nil {
+		return nil, fmt.Errorf("get network: %w", err)
+	}
+	path := conf.Config.BlockCache
+	if path == "" {
+		path = filepath.Join(conf.Config.DirPathConf.DataDir, fmt.Sprintf("blocks-%d.db", networkId))
+	}
+	cache, err := blockcache.Open(path, time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	cleared, err := cache.SetNetwork(strconv.FormatInt(networkId, 10))
+	if err != nil {
+		cache.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if cleared {
+		log.Warnf("block cache %s held the blocks of another network, they are removed", path)
+	}
+	return cache, nil
+}
+
+// blockCache returns the block cache of the current client, nil when it is disabled or can't be opened
+func blockCache() *blockcache.Cache {
+	s := &blockCacheState
+	if s.client == models.Client {
+		return s.cache
+	}
+	if s.cache != nil {
+		s.cache.Close()
+	}
+	s.client, s.cache = models.Client, nil
+	if noBlockCache || conf.Config.BlockCache == blockCacheOff {
+		return nil
+	}
+	maxBlock, err := models.Client.GetMaxBlockID()
+	if err != nil {
+		log.Warnf("block cache not used: get max block: %s", err)
+		return nil
+	}
+	cache, err := openBlockCacheFile()
+	if err != nil {
+		log.Warnf("block cache not used: %s", err)
+		return nil
+	}
+	s.cache, s.cacheTo = cache, maxBlock-blockCacheDepth
+	return cache
+}
+
+// storeBlocks adds the blocks old enough to the cache, a block without a hash of 32 bytes isn't stored.
+// The hash is the one of the block in the detailedBlocks response, getBlockInfo is requested only by cachedBlockInfo
+func storeBlocks(cache *blockcache.Cache, list ...*response.BlockDetailedInfo) {
+	old := make([]*response.BlockDetailedInfo, 0, len(list))
+	for _, info := range list {
+		if info.Header.BlockId > blockCacheState.cacheTo {
+			continue
+		}
+		if hash, err := hex.DecodeString(info.Hash); err != nil || len(hash) != 32 {
+			log.Warnf("block cache: block %d isn't cached, hash [%s] invalid", info.Header.BlockId, info.Hash)
+			continue
+		}
+		old = append(old, info)
+	}
+	if err := cache.PutBlocks(old...); err != nil {
+		log.Warnf("block cache: %s", err)
+	}
+}
+
+// cachedBlock returns the block of the id or hash, requesting it from the node when it isn't cached
+func cachedBlock(bh request.BlockIdOrHash) (*response.BlockDetailedInfo, error) {
+	cache := blockCache()
+	if cache != nil {
+		var info *response.BlockDetailedInfo
+		var err error
+		if bh.Hash != "" {
+			info, err = cache.BlockByHash(strings.ToLower(bh.Hash))
+		} else {
+			info, err = cache.Block(bh.Id)
+		}
+		if err != nil {
+			log.Warnf("block cache: %s", err)
+		} else if info != nil {
+			return info, nil
+		}
+	}
+	info, err := models.DetailedBlock(bh)
+	if err != nil || info == nil || cache == nil {
+		return info, err
+	}
+	// only a block matching the request is cached
+	if bh.Hash != "" && !strings.EqualFold(info.Hash, bh.Hash) || bh.Hash == "" && info.Header.BlockId != bh.Id {
+		return info, nil
+	}
+	storeBlocks(cache, info)
+	return info, nil
+}
+
+// scanBlocks calls fn with the blocks from..to in order, the cached blocks are read from the cache
+// and the others are requested a page at a time
+func scanBlocks(from, to int64, fn func(*response.BlockDetailedInfo) error) error {
+	cache := blockCache()
+	if cache == nil {
+		return fetchBlocks(from, to, fn)
+	}
+	for id := from; id <= to; {
+		info, err := cache.Block(id)
+		if err != nil {
+			return fmt.Errorf("block cache: %w", err)
+		}
+		if info != nil {
+			if err = fn(info); err != nil {
+				return err
+			}
+			id++
+			continue
+		}
+		end := id
+		for end < to {
+			next, err := cache.Block(end + 1)
+			if err != nil {
+				return fmt.Errorf("block cache: %w", err)
+			}
+			if next != nil {
+				break
+			}
+			end++
+		}
+		var fetched []*response.BlockDetailedInfo
+		err = fetchBlocks(id, end, func(info *response.BlockDetailedInfo) error {
+			fetched = append(fetched, info)
+			return fn(info)
+		})
+		if err != nil {
+			return err
+		}
+		storeBlocks(cache, fetched...)
+		id = end + 1
+	}
+	return nil
+}
+
+// cachedBlocks returns the blocks blockId..blockId+count-1 like detailedBlocks, from the cache when all are cached
+func cachedBlocks(blockId, count int64) (*map[int64]response.BlockDetailedInfo, error) {
+	cache := blockCache()
+	if cache != nil && count > 0 && count <= blockPageSize {
+		result := make(map[int64]response.BlockDetailedInfo, count)
+		for id := blockId; id < blockId+count; id++ {
+			info, err := cache.Block(id)
+			if err != nil || info == nil {
+				break
+			}
+			result[id] = *info
+		}
+		if int64(len(result)) == count {
+			return &result, nil
+		}
+	}
+	result, err := models.DetailedBlocks(blockId, count)
+	if err != nil || result == nil || cache == nil {
+		return result, err
+	}
+	list := make([]*response.BlockDetailedInfo, 0, len(*result))
+	for id, info := range *result {
+		if info.Header.BlockId == id {
+			info := info
+			list = append(list, &info)
+		}
+	}
+	storeBlocks(cache, list...)
+	return result, nil
+}
+
+// cachedBlocksTxInfo returns the transactions of the blocks blockId..blockId+count-1 like blocksTxInfo,
+// with the cache the blocks are read by cachedBlocks so that the fetched ones are cached
+func cachedBlocksTxInfo(blockId, count int64) (*map[int64][]response.TxInfo, error) {
+	if blockCache() == nil {
+		return models.Client.BlocksTxInfo(blockId, count)
+	}
+	blocks, err := cachedBlocks(blockId, count)
+	if err != nil || blocks == nil {
+		return nil, err
+	}
+	result := make(map[int64][]response.TxInfo, len(*blocks))
+	for id, info := range *blocks {
+		result[id] = info.Transactions
+	}
+	return &result, nil
+}
+
+// cachedBlockInfo returns the block info like getBlockInfo, from the cache when it is cached
+func cachedBlockInfo(blockId int64) (*response.BlockInfoResult, error) {
+	cache := blockCache()
+	if cache != nil {
+		info, err := cache.Info(blockId)
+		if err != nil {
+			log.Warnf("block cache: %s", err)
+		} else if info != nil {
+			return info, nil
+		}
+	}
+	info, err := models.Client.GetBlockInfo(blockId)
+	if err != nil || info == nil || cache == nil || blockId > blockCacheState.cacheTo {
+		return info, err
+	}
+	if err = cache.PutInfo(blockId, info); err != nil {
+		log.Warnf("block cache: %s", err)
+	}
+	return info, nil
+}
+
+func openBlockCacheCmd() (*blockcache.Cache, error) {
+	cache, err := openBlockCacheFile()
+	if err != nil {
+		return nil, clierr.Wrap(clierr.Config, err, "Open Block Cache Failed")
+	}
+	return cache, nil
+}
+
+func cacheStats(cmd *cobra.Command, params []string) error {
+	cache, err := openBlockCacheCmd()
+	if err != nil {
+		return err
+	}
+	defer cache.Close()
+	stats, err := cache.Stats()
+	if err != nil {
+		return clierr.Wrap(clierr.Config, err, "Block Cache Stats Failed")
+	}
+	return printResult(stats)
+}
+
+func cachePrune(cmd *cobra.Command, params []string) error {
+	set := 0
+	for _, ok := range []bool{cachePruneBefore > 0, cachePruneKeep > 0, cachePruneAll} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return clierr.New(clierr.Argument, "one of --before, --keep or --all is required")
+	}
+	cache, err := openBlockCacheCmd()
+	if err != nil {
+		return err
+	}
+	defer cache.Close()
+	blocks, infos, err := cache.Ids()
+	if err != nil {
+		return clierr.Wrap(clierr.Config, err, "Read Block Cache Failed")
+	}
+	before := cachePruneBefore
+	switch {
+	case cachePruneAll:
+		before = 1<<63 - 1
+	case cachePruneKeep > 0:
+		before = 0
+		if int64(len(blocks)) > cachePruneKeep {
+			before = blocks[int64(len(blocks))-cachePruneKeep]
+		}
+	}
+	removed := make(map[int64]bool)
+	for _, id := range append(blocks, infos...) {
+		if id < before {
+			removed[id] = true
+		}
+	}
+	ids := make([]int64, 0, len(removed))
+	for id := range removed {
+		ids = append(ids, id)
+	}
+	if err = cache.Delete(ids...); err != nil {
+		return clierr.Wrap(clierr.Config, err, "Prune Block Cache Failed")
+	}
+	left := 0
+	for _, id := range blocks {
+		if id >= before {
+			left++
+		}
+	}
+	return printResult(map[string]int{"removed": len(ids), "blocks": left})
+}
+
+// idRuns calls fn with the runs of consecutive ids, the ids are in order
+func idRuns(ids []int64, fn func(from, to int64) error) error {
+	for i := 0; i < len(ids); {
+		j := i
+		for j+1 < len(ids) && ids[j+1] == ids[j]+1 {
+			j++
+		}
+		if err := fn(ids[i], ids[j]); err != nil {
+			return err
+		}
+		i = j + 1
+	}
+	return nil
+}
+
+func cacheRebuild(cmd *cobra.Command, params []string) error {
+	cache, err := openBlockCacheCmd()
+	if err != nil {
+		return err
+	}
+	defer cache.Close()
+	blocks, infos, err := cache.Ids()
+	if err != nil {
+		return clierr.Wrap(clierr.Config, err, "Read Block Cache Failed")
+	}
+	maxBlock, err := models.Client.GetMaxBlockID()
+	if err != nil {
+		return clierr.Wrap(clierr.RPC, err, "Get Max Block Failed")
+	}
+	type rebuildResult struct {
+		Blocks       int `json:"blocks"`
+		Replaced     int `json:"replaced"`
+		Removed      int `json:"removed"`
+		Infos        int `json:"infos"`
+		InfosRemoved int `json:"infos_removed"`
+	}
+	result := rebuildResult{Blocks: len(blocks), Infos: len(infos)}
+
+	// the blocks after the max block were rolled back
+	var missing []int64
+	for len(blocks) > 0 && blocks[len(blocks)-1] > maxBlock {
+		missing = append(missing, blocks[len(blocks)-1])
+		blocks = blocks[:len(blocks)-1]
+	}
+	if err = cache.Delete(missing...); err != nil {
+		return clierr.Wrap(clierr.Config, err, "Rebuild Block Cache Failed")
+	}
+	result.Removed = len(missing)
+	err = idRuns(blocks, func(from, to int64) error {
+		var changed []*response.BlockDetailedInfo
+		err := fetchBlocks(from, to, func(info *response.BlockDetailedInfo) error {
+			old, err := cache.Block(info.Header.BlockId)
+			if err != nil {
+				return err
+			}
+			if old == nil || old.Hash != info.Hash {
+				changed = append(changed, info)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		result.Replaced += len(changed)
+		return cache.PutBlocks(changed...)
+	})
+	if err != nil {
+		return clierr.Wrap(clierr.RPC, err, "Rebuild Block Cache Failed")
+	}
+
+	// a block info is checked against the cached block of its id, without one the blocks are requested
+	// like the cached blocks, a page at a time
+	var stale, unchecked []int64
+	for _, id := range infos {
+		if id > maxBlock {
+			stale = append(stale, id)
+			continue
+		}
+		b, err := cache.Block(id)
+		if err != nil {
+			return clierr.Wrap(clierr.Config, err, "Rebuild Block Cache Failed")
+		}
+		if b == nil {
+			unchecked = append(unchecked, id)
+			continue
+		}
+		if info, err := cache.Info(id); err != nil || info == nil {
+			stale = append(stale, id)
+		}
+	}
+	err = idRuns(unchecked, func(from, to int64) error {
+		return fetchBlocks(from, to, func(b *response.BlockDetailedInfo) error {
+			info, err := cache.Info(b.Header.BlockId)
+			if err != nil || info == nil || info.Hash != b.Hash {
+				stale = append(stale, b.Header.BlockId)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return clierr.Wrap(clierr.RPC, err, "Rebuild Block Cache Failed")
+	}
+	if err = cache.DeleteInfos(stale...); err != nil {
+		return clierr.Wrap(clierr.Config, err, "Rebuild Block Cache Failed")
+	}
+	result.InfosRemoved = len(stale)
+	return printResult(result)
+}
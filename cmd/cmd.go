@@ -1,10 +1,10 @@
 package cmd
 
 import (
-	"context"
 	"github.com/IBAX-io/go-ibax-sdk/packages/request"
 	"github.com/spf13/cobra"
 	"sync"
+	"time"
 )
 
 var cmdList []*cobra.Command
@@ -16,7 +16,7 @@ var (
 		Short: "Returns an ecosystem list containing rolues registered with the specified address",
 		Long: `
 Request:
-	Account			(string) Account Address: "xxxx-xxxx-xxxx-xxxx-xxxx"
+	Account			(string) Account Address: "xxxx-xxxx-xxxx-xxxx-xxxx" or @alias of the address book
 
 Returns a json object key information.
 Result:
@@ -40,8 +40,8 @@ Result:
 		SuggestFor: []string{"getKeyInfo"},
 		Example:    "./ibax-cli getKeyInfo 0666-7782-xxxx-xxxx-3160",
 		Args:       cobra.ExactArgs(1),
-		PreRun:     loadConfigPre,
-		Run:        getKeysInfoCmd,
+		PreRunE:    loadConfigPre,
+		RunE:       getKeysInfoCmd,
 	}
 
 	getBalance = &cobra.Command{
@@ -49,7 +49,7 @@ Result:
 		Short: "Get Account Balance",
 		Long: `
 Request:
-	Account   				(string,optional) Account Address: "xxxx-xxxx-xxxx-xxxx-xxxx",default: current account (if exist)
+	Account   				(string,optional) Account Address: "xxxx-xxxx-xxxx-xxxx-xxxx" or @alias,default: current account (if exist)
 	EcosystemId   			(number,optional) Ecosystem Id,default: ecosystem id 1
 
 Returns a json object Balance information.
@@ -59,14 +59,19 @@ Result:
 		"digits": n,			(number) precision.
 		"total": "str",			(string) he minimum unit account total balance (amount + utxo)
 		"utxo": "str",			(string) The smallest unit UTXO account balance.
-		"token_symbol": "str"	(string) token symbol
+		"token_symbol": "str",	(string) token symbol
+		"formatted": {			(json object) The balances in tokens with the token symbol: "1.5 IBAX"
+			"amount": "str",	(string) amount / 10^digits
+			"utxo": "str",		(string) utxo / 10^digits
+			"total": "str"		(string) total / 10^digits
+		}
 	}
 `,
 		SuggestFor: []string{"getBalance"},
 		Example:    "./ibax-cli getBalance 0666-7782-xxxx-xxxx-3160",
 		Args:       cobra.RangeArgs(0, 2),
-		PreRun:     loadConfigPre,
-		Run:        getBalanceCmd,
+		PreRunE:    loadConfigPre,
+		RunE:       getBalanceCmd,
 	}
 
 	getVersion = &cobra.Command{
@@ -82,8 +87,8 @@ Result:
 `,
 		SuggestFor: []string{"getVersion"},
 		Example:    "./ibax-cli getVersion",
-		PreRun:     loadConfigPre,
-		Run:        getVersionCmd,
+		PreRunE:    loadConfigPre,
+		RunE:       getVersionCmd,
 	}
 
 	getConfig = &cobra.Command{
@@ -96,12 +101,14 @@ Request:
 Returns String.
 Result:
 	"wss://node21.ibax.io:8330"		(string) Centrifugo Server URL
+
+See "subscribe" for the notifications of the account.
 `,
 		SuggestFor: []string{"getConfig"},
 		Example:    "./ibax-cli getConfig centrifugo",
-		PreRun:     loadConfigPre,
+		PreRunE:    loadConfigPre,
 		Args:       cobra.ExactArgs(1),
-		Run:        getChainConfigCmd,
+		RunE:       getChainConfigCmd,
 	}
 
 	ecosystemCount = &cobra.Command{
@@ -117,8 +124,8 @@ Result:
 `,
 		SuggestFor: []string{"ecosystemCount"},
 		Example:    "./ibax-cli ecosystemCount",
-		PreRun:     loadConfigPre,
-		Run:        getEcosystemCountCmd,
+		PreRunE:    loadConfigPre,
+		RunE:       getEcosystemCountCmd,
 	}
 
 	maxBlock = &cobra.Command{
@@ -134,8 +141,8 @@ Result:
 `,
 		SuggestFor: []string{"maxBlock"},
 		Example:    "./ibax-cli maxBlock",
-		PreRun:     loadConfigPre,
-		Run:        getMaxBlockCmd,
+		PreRunE:    loadConfigPre,
+		RunE:       getMaxBlockCmd,
 	}
 
 	txCount = &cobra.Command{
@@ -151,8 +158,8 @@ Result:
 `,
 		SuggestFor: []string{"transactionCount"},
 		Example:    "./ibax-cli transactionCount",
-		PreRun:     loadConfigPre,
-		Run:        getTxCountCmd,
+		PreRunE:    loadConfigPre,
+		RunE:       getTxCountCmd,
 	}
 
 	keysCount = &cobra.Command{
@@ -168,8 +175,8 @@ Result:
 `,
 		SuggestFor: []string{"keysCount"},
 		Example:    "./ibax-cli keysCount",
-		PreRun:     loadConfigPre,
-		Run:        getKeysCountCmd,
+		PreRunE:    loadConfigPre,
+		RunE:       getKeysCountCmd,
 	}
 
 	honorNodesCount = &cobra.Command{
@@ -185,8 +192,8 @@ Result:
 `,
 		SuggestFor: []string{"honorNodesCount"},
 		Example:    "./ibax-cli honorNodesCount",
-		PreRun:     loadConfigPre,
-		Run:        getHonorNodesCountCmd,
+		PreRunE:    loadConfigPre,
+		RunE:       getHonorNodesCountCmd,
 	}
 
 	detailedBlocks = &cobra.Command{
@@ -196,6 +203,7 @@ Result:
 Request:
 	BlockId				(number) The starting block height to query
 	Count				(number,optional) The number of blocks, the default is 25, the maximum request is 100
+	"block range" and "block txs" page through ranges of any size
 
 Returns a json object block detail information.
 Result:
@@ -233,9 +241,9 @@ Result:
 `,
 		SuggestFor: []string{"detailedBlocks"},
 		Example:    "./ibax-cli detailedBlocks [BlockId] [Count]",
-		PreRun:     loadConfigPre,
+		PreRunE:    loadConfigPre,
 		Args:       cobra.RangeArgs(1, 2),
-		Run:        detailBlocksCmd,
+		RunE:       detailBlocksCmd,
 	}
 
 	getBlockInfo = &cobra.Command{
@@ -260,9 +268,9 @@ Result:
 `,
 		SuggestFor: []string{"getBlockInfo"},
 		Example:    "./ibax-cli getBlockInfo [BlockId]",
-		PreRun:     loadConfigPre,
+		PreRunE:    loadConfigPre,
 		Args:       cobra.ExactArgs(1),
-		Run:        getBlockInfoCmd,
+		RunE:       getBlockInfoCmd,
 	}
 
 	blocksTxInfo = &cobra.Command{
@@ -272,6 +280,7 @@ Result:
 Request:
 	BlockId			(number) The starting block height to query
 	Count			(number,optional) The number of blocks, the default is 25, the maximum request is 100
+	"block txs" pages through ranges of any size and filters the transactions
 
 Returns a json object block transaction information.
 Result:
@@ -288,9 +297,9 @@ Result:
 `,
 		SuggestFor: []string{"blocksTxInfo"},
 		Example:    "./ibax-cli blocksTxInfo [BlockId] [Count]",
-		PreRun:     loadConfigPre,
+		PreRunE:    loadConfigPre,
 		Args:       cobra.RangeArgs(1, 2),
-		Run:        getBlocksTxInfoCmd,
+		RunE:       getBlocksTxInfoCmd,
 	}
 
 	getTableCount = &cobra.Command{
@@ -316,9 +325,9 @@ Result:
 `,
 		SuggestFor: []string{"getTableCount"},
 		Example:    "./ibax-cli getTableCount [Offset] [Limit]",
-		PreRun:     loginPre,
+		PreRunE:    loginPre,
 		Args:       cobra.RangeArgs(0, 2),
-		Run:        getTableCountCmd,
+		RunE:       getTableCountCmd,
 	}
 
 	getTable = &cobra.Command{
@@ -348,9 +357,9 @@ Result:
 `,
 		SuggestFor: []string{"getTable"},
 		Example:    "./ibax-cli getTable [TableName]",
-		PreRun:     loginPre,
+		PreRunE:    loginPre,
 		Args:       cobra.ExactArgs(1),
-		Run:        getTableCmd,
+		RunE:       getTableCmd,
 	}
 
 	getSections = &cobra.Command{
@@ -385,9 +394,9 @@ Result:
 `,
 		SuggestFor: []string{"getSections"},
 		Example:    "./ibax-cli getSections [TableName]",
-		PreRun:     loginPre,
+		PreRunE:    loginPre,
 		Args:       cobra.RangeArgs(0, 3),
-		Run:        getSectionsCmd,
+		RunE:       getSectionsCmd,
 	}
 
 	getPageRow = &cobra.Command{
@@ -411,9 +420,9 @@ Result:
 `,
 		SuggestFor: []string{"getPageRow"},
 		Example:    `./ibax-cli getPageRow [Name]`,
-		PreRun:     loginPre,
+		PreRunE:    loginPre,
 		Args:       cobra.ExactArgs(1),
-		Run:        getPageRowCmd,
+		RunE:       getPageRowCmd,
 	}
 
 	getMenuRow = &cobra.Command{
@@ -435,9 +444,9 @@ Result:
 `,
 		SuggestFor: []string{"getMenuRow"},
 		Example:    "./ibax-cli getMenuRow [Name]",
-		PreRun:     loginPre,
+		PreRunE:    loginPre,
 		Args:       cobra.ExactArgs(1),
-		Run:        getMenuRowCmd,
+		RunE:       getMenuRowCmd,
 	}
 
 	getSnippetRow = &cobra.Command{
@@ -458,9 +467,9 @@ Result:
 `,
 		SuggestFor: []string{"getSnippetRow"},
 		Example:    "./ibax-cli getSnippetRow [Name]",
-		PreRun:     loginPre,
+		PreRunE:    loginPre,
 		Args:       cobra.ExactArgs(1),
-		Run:        getSnippetRowCmd,
+		RunE:       getSnippetRowCmd,
 	}
 
 	getAppContent = &cobra.Command{
@@ -495,9 +504,9 @@ Result:
 `,
 		SuggestFor: []string{"getAppContent"},
 		Example:    "./ibax-cli getAppContent [Id]",
-		PreRun:     loginPre,
+		PreRunE:    loginPre,
 		Args:       cobra.ExactArgs(1),
-		Run:        getAppContentCmd,
+		RunE:       getAppContentCmd,
 	}
 
 	appParams = &cobra.Command{
@@ -528,9 +537,9 @@ Result:
 `,
 		SuggestFor: []string{"appParams"},
 		Example:    "./ibax-cli appParams [AppId] [Names] [EcosystemId] [Offset] [Limit]",
-		PreRun:     loginPre,
+		PreRunE:    loginPre,
 		Args:       cobra.RangeArgs(1, 5),
-		Run:        appParamsCmd,
+		RunE:       appParamsCmd,
 	}
 
 	ecosystemParams = &cobra.Command{
@@ -559,9 +568,9 @@ Result:
 `,
 		SuggestFor: []string{"ecosystemParams"},
 		Example:    "./ibax-cli ecosystemParams [EcosystemId] [Names] [Offset] [Limit]",
-		PreRun:     loginPre,
+		PreRunE:    loginPre,
 		Args:       cobra.RangeArgs(0, 4),
-		Run:        ecosystemParamsCmd,
+		RunE:       ecosystemParamsCmd,
 	}
 
 	systemParams = &cobra.Command{
@@ -589,9 +598,9 @@ Result:
 `,
 		SuggestFor: []string{"systemParams"},
 		Example:    "./ibax-cli systemParams [Names] [Offset] [Limit]",
-		PreRun:     loginPre,
+		PreRunE:    loginPre,
 		Args:       cobra.RangeArgs(0, 3),
-		Run:        systemParamsCmd,
+		RunE:       systemParamsCmd,
 	}
 
 	rowParams idObject
@@ -617,9 +626,9 @@ Result:
 `,
 		SuggestFor: []string{"getRow"},
 		Example:    "./ibax-cli getRow [TableName] [Columns] [WhereColumn]",
-		PreRun:     loginPre,
+		PreRunE:    loginPre,
 		Args:       cobra.RangeArgs(1, 3),
-		Run:        getRowCmd,
+		RunE:       getRowCmd,
 	}
 
 	getHistory = &cobra.Command{
@@ -644,9 +653,9 @@ Result:
 `,
 		SuggestFor: []string{"getHistory"},
 		Example:    "./ibax-cli getHistory [TableName] [Id]",
-		PreRun:     loginPre,
+		PreRunE:    loginPre,
 		Args:       cobra.ExactArgs(2),
-		Run:        getHistoryCmd,
+		RunE:       getHistoryCmd,
 	}
 
 	getListParams struct {
@@ -680,11 +689,11 @@ Result:
 `,
 		SuggestFor: []string{"getList"},
 		Example:    `./ibax-cli getList [TableName] -w='{"id": [tableId]}' -c="amount,ecosystem" -l=3 -t=1 -r="ecosystem desc`,
-		PreRun: func(cmd *cobra.Command, args []string) {
-			loginPre(cmd, args)
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return loginPre(cmd, args)
 		},
 		Args: cobra.ExactArgs(1),
-		Run:  getListCmd,
+		RunE: getListCmd,
 	}
 
 	blockTxCount = &cobra.Command{
@@ -700,9 +709,9 @@ Result:
 `,
 		SuggestFor: []string{"blockTxCount"},
 		Example:    "./ibax-cli blockTxCount [BlockOrHash]",
-		PreRun:     loadConfigPre,
+		PreRunE:    loadConfigPre,
 		Args:       cobra.ExactArgs(1),
-		Run:        blockTxCountCmd,
+		RunE:       blockTxCountCmd,
 	}
 
 	detailedBlock = &cobra.Command{
@@ -746,9 +755,9 @@ Result:
 `,
 		SuggestFor: []string{"detailedBlock"},
 		Example:    "./ibax-cli detailedBlock [BlockOrHash]",
-		PreRun:     loadConfigPre,
+		PreRunE:    loadConfigPre,
 		Args:       cobra.ExactArgs(1),
-		Run:        detailedBlockCmd,
+		RunE:       detailedBlockCmd,
 	}
 
 	ecosystemInfo = &cobra.Command{
@@ -778,9 +787,9 @@ Result:
 `,
 		SuggestFor: []string{"ecosystemInfo"},
 		Example:    "./ibax-cli ecosystemInfo [EcosystemId]",
-		PreRun:     loadConfigPre,
+		PreRunE:    loadConfigPre,
 		Args:       cobra.ExactArgs(1),
-		Run:        ecosystemInfoCmd,
+		RunE:       ecosystemInfoCmd,
 	}
 
 	getMemberInfo = &cobra.Command{
@@ -788,7 +797,7 @@ Result:
 		Short: "Get Ecosystem information",
 		Long: `
 Request:
-	Account 			(string) Account Address (xxxx-xxxx-xxxx-xxxx-xxxx)
+	Account 			(string) Account Address (xxxx-xxxx-xxxx-xxxx-xxxx) or @alias
 	EcosystemId 		(number) Ecosystem Id
 
 Returns a json object for Ecosystem information
@@ -802,9 +811,9 @@ Result:
 `,
 		SuggestFor: []string{"getMemberInfo"},
 		Example:    "./ibax-cli getMemberInfo [Account] [EcosystemId]",
-		PreRun:     loadConfigPre,
+		PreRunE:    loadConfigPre,
 		Args:       cobra.ExactArgs(2),
-		Run:        getMemberInfoCmd,
+		RunE:       getMemberInfoCmd,
 	}
 
 	binaryFileName string
@@ -826,9 +835,9 @@ Result:
 `,
 		SuggestFor: []string{"binaryVerify"},
 		Example:    "./ibax-cli binaryVerify [BinaryId] [BinaryHash]",
-		PreRun:     loginPre,
+		PreRunE:    loginPre,
 		Args:       cobra.ExactArgs(2),
-		Run:        binaryVerifyCmd,
+		RunE:       binaryVerifyCmd,
 	}
 
 	exportFileName string
@@ -849,9 +858,9 @@ Result:
 `,
 		SuggestFor: []string{"export"},
 		Example:    "./ibax-cli export",
-		PreRun:     loginPre,
+		PreRunE:    loginPre,
 		Args:       cobra.ExactArgs(1),
-		Run:        exportCmd,
+		RunE:       exportCmd,
 	}
 
 	importFileName string
@@ -873,8 +882,8 @@ Result:
 `,
 		SuggestFor: []string{"import"},
 		Example:    "./ibax-cli import",
-		PreRun:     loginPre,
-		Run:        importCmd,
+		PreRunE:    loginPre,
+		RunE:       importCmd,
 	}
 
 	encodeFileName string
@@ -891,7 +900,7 @@ Returns Encode data
 		SuggestFor: []string{"base64Encode"},
 		Example:    "./ibax-cli base64Encode",
 		Args:       cobra.MaximumNArgs(1),
-		Run:        base64EncodeCmd,
+		RunE:       base64EncodeCmd,
 	}
 
 	decodeFileName string
@@ -908,21 +917,10 @@ Returns decode data
 		SuggestFor: []string{"base64Decode"},
 		Example:    "./ibax-cli base64Decode [data]",
 		Args:       cobra.MaximumNArgs(1),
-		Run:        base64DecodeCmd,
+		RunE:       base64DecodeCmd,
 	}
 )
 
-func hasErrorContext(cmd *cobra.Command) bool {
-	ctx := cmd.Context()
-	if val := ctx.Value("error"); val != nil {
-		return true
-	}
-	return false
-}
-func cleanErrorContext(cmd *cobra.Command) {
-	cmd.SetContext(context.Background())
-}
-
 type idObject struct {
 	lock sync.Mutex
 	Id   int64
@@ -933,6 +931,16 @@ func initFlags() {
 	getRow.MarkFlagRequired("id")
 
 	callContract.Flags().StringVarP(&contractParamsFile, "file", "f", "", "Contract Params File Name,json object,priority")
+	callContract.Flags().BoolVarP(&contractInteractive, "interactive", "i", false, "prompt the params field by field and confirm before sending")
+	callContract.Flags().BoolVar(&dryRun, "dry-run", false, "check the call and estimate the fee without sending it")
+	for _, c := range []*cobra.Command{callUtxo, utxoTransferCmd, utxoToContractCmd, utxoFromContractCmd} {
+		c.Flags().BoolVar(&dryRun, "dry-run", false, "check the call and estimate the fee without sending it")
+	}
+	for _, c := range []*cobra.Command{callContract, callUtxo, utxoTransferCmd, utxoToContractCmd, utxoFromContractCmd} {
+		c.Flags().BoolVar(&txAsync, "async", false, "return the transaction hash after sending, without waiting for the block")
+		c.Flags().Int64Var(&txConfirmations, "confirmations", 0, "wait until the number of blocks on top of the transaction, its own block included")
+		c.Flags().DurationVar(&txTimeout, "timeout", time.Minute, "time to wait for the confirmations")
+	}
 
 	getList.Flags().IntVarP(&getListParams.Limit, "limit", "l", 0, "the number of Omitempty entries,default 25")
 	getList.Flags().IntVarP(&getListParams.Offset, "offset", "t", 0, "offset,default 0")
@@ -975,6 +983,7 @@ func initCmdList() {
 	cmdList = append(cmdList, getBalance)
 	cmdList = append(cmdList, getVersion)
 	cmdList = append(cmdList, getConfig)
+	cmdList = append(cmdList, subscribeCmd)
 	cmdList = append(cmdList, ecosystemCount)
 	cmdList = append(cmdList, maxBlock)
 	cmdList = append(cmdList, txCount)
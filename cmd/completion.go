@@ -1,7 +1,7 @@
 package cmd
 
 import (
-	log "github.com/sirupsen/logrus"
+	"github.com/IBAX-io/ibax-cli/packages/clierr"
 	"github.com/spf13/cobra"
 	"os"
 	"runtime"
@@ -53,7 +53,7 @@ PS> ibax-cli completion powershell  (default: ibax-cli.ps1)
 `,
 	ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
 	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		switch args[0] {
 		case "bash":
 			if isDefault {
@@ -64,29 +64,28 @@ PS> ibax-cli completion powershell  (default: ibax-cli.ps1)
 				}
 				fi, err := os.Create(path)
 				if err != nil {
-					log.Fatal(err)
+					return clierr.Wrap(clierr.Unknown, err, "create completion file")
 				}
 				defer fi.Close()
-				cmd.Root().GenBashCompletion(fi)
-			} else {
-				cmd.Root().GenBashCompletion(os.Stdout)
+				return cmd.Root().GenBashCompletion(fi)
 			}
+			return cmd.Root().GenBashCompletion(os.Stdout)
 		case "zsh":
-			cmd.Root().GenZshCompletion(os.Stdout)
+			return cmd.Root().GenZshCompletion(os.Stdout)
 		case "fish":
-			cmd.Root().GenFishCompletion(os.Stdout, true)
+			return cmd.Root().GenFishCompletion(os.Stdout, true)
 		case "powershell":
 			if isDefault {
 				create, err := os.Create("ibax-cli.ps1")
 				if err != nil {
-					log.Fatal(err)
+					return clierr.Wrap(clierr.Unknown, err, "create completion file")
 				}
 				defer create.Close()
-				cmd.Root().GenPowerShellCompletion(create)
-			} else {
-				cmd.Root().GenPowerShellCompletion(os.Stdout)
+				return cmd.Root().GenPowerShellCompletion(create)
 			}
+			return cmd.Root().GenPowerShellCompletion(os.Stdout)
 		}
+		return nil
 	},
 }
 var isDefault bool
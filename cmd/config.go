@@ -1,16 +1,18 @@
 package cmd
 
 import (
-	"context"
+	"encoding/hex"
 	"fmt"
 	"github.com/IBAX-io/go-ibax-sdk/packages/client"
 	"github.com/IBAX-io/go-ibax/packages/common/crypto"
 	"github.com/IBAX-io/ibax-cli/conf"
 	"github.com/IBAX-io/ibax-cli/models"
+	"github.com/IBAX-io/ibax-cli/packages/clierr"
 	"github.com/IBAX-io/ibax-cli/packages/consts"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"os"
 	"path/filepath"
 )
 
@@ -18,32 +20,39 @@ import (
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Initial config generation",
-	Run: func(cmd *cobra.Command, args []string) {
+	Long: `
+Generates config.yml from the flags, an existing file is only overwritten with --force.
+The subcommands read and edit an existing config.yml.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if nonce == 1 {
-			log.Info("Please exit Console")
-			return
+			return clierr.New(clierr.Argument, "Please exit Console")
 		}
 		// Error omitted because we have default flag value
 		configPath, _ := cmd.Flags().GetString("config")
 
 		err := conf.FillRuntimePaths()
 		if err != nil {
-			log.WithError(err).Fatal("Filling config")
+			return clierr.Wrap(clierr.Config, err, "Filling config")
 		}
 
 		if configPath == "" {
 			configPath = filepath.Join(conf.Config.DirPathConf.DataDir, consts.DefaultConfigFile)
 		}
+		if _, err := os.Stat(configPath); err == nil && !forceConfig {
+			return clierr.New(clierr.Argument, "config file %s exists, use --force to overwrite it", configPath)
+		}
 		err = viper.Unmarshal(&conf.Config)
 		if err != nil {
-			log.WithError(err).Fatal("Marshalling config to global struct variable")
+			return clierr.Wrap(clierr.Config, err, "Marshalling config to global struct variable")
 		}
 
 		err = conf.SaveConfig(configPath)
 		if err != nil {
-			log.WithError(err).Fatal("Saving config")
+			return clierr.Wrap(clierr.Config, err, "Saving config")
 		}
 		log.Infof("Config is saved to %s", configPath)
+		return nil
 	},
 }
 
@@ -57,36 +66,67 @@ func init() {
 	cmdFlags.Int64Var(&conf.Config.Ecosystem, "ecosystem", 1, "login ecosystem id")
 	cmdFlags.StringVar(&conf.Config.RpcConnect, "connect", consts.DefaultConnect, "Send commands to node running on <connect>")
 	cmdFlags.IntVar(&conf.Config.RpcPort, "port", consts.DefaultPort, "Connect to JSON-RPC on <port>")
+	cmdFlags.BoolVar(&forceConfig, "force", false, "overwrite an existing config file")
+
+	configCmd.AddCommand(
+		configShowCmd,
+		configGetCmd,
+		configSetCmd,
+		configValidateCmd,
+		configPathCmd,
+	)
+	for _, subCommand := range configCmd.Commands() {
+		for k, v := range subCommand.SuggestFor {
+			subCommand.SuggestFor[k] = configCmd.Use + " " + v
+			models.AddWordsCompletions(subCommand.SuggestFor)
+		}
+	}
 }
 
 // Load the configuration from file
-func loadConfig(cmd *cobra.Command) {
+func loadConfig(cmd *cobra.Command) error {
 	err := conf.LoadConfig(conf.Config.ConfigPath)
 	if err != nil {
-		if models.IsConsoleMode() {
-			ctx := cmd.Context()
-			ctx = context.WithValue(ctx, "error", err.Error())
-			cmd.SetContext(ctx)
-			models.SendErrSignal(fmt.Errorf("loading config ,err: %s", err.Error()), false)
-			return
+		return clierr.Wrap(clierr.Config, err, "loading config")
+	}
+	err = conf.Config.ApplyProfile(profileName)
+	if err != nil {
+		return clierr.Wrap(clierr.Config, err, "loading profile")
+	}
+	algo := configAlgorithm()
+	err = initAlgorithm(algo)
+	if err != nil {
+		return clierr.Wrap(clierr.Config, err, "config algorithm")
+	}
+	if conf.Config.PrivateKey == "" && conf.Config.Keystore != "" {
+		privateKey, ks, err := unlockKeystore(conf.Config.Keystore)
+		if err != nil {
+			return clierr.Wrap(clierr.Auth, err, "unlock keystore")
+		}
+		if tag, ok, _ := keystoreAlgorithm(ks); ok && tag != algo {
+			return clierr.New(clierr.Config, "keystore algorithm %s doesn't match the config algorithm %s", tag, algo)
 		}
-		log.WithError(err).Fatal("Loading config")
+		conf.Config.PrivateKey = hex.EncodeToString(privateKey)
 	}
 	rpcHost := joinHost(conf.Config.RpcConnect, conf.Config.RpcPort)
 	conf.UpdateSdkConfig(rpcHost)
-
+	return nil
 }
 
 func joinHost(address string, port int) (host string) {
 	return fmt.Sprintf("%s:%d", address, port)
 }
 
-func loadConfigPre(cmd *cobra.Command, args []string) {
+func loadConfigPre(cmd *cobra.Command, args []string) error {
 	if models.Client != nil {
-		return
+		return nil
+	}
+	err := loadConfig(cmd)
+	if err != nil {
+		return err
 	}
-	loadConfig(cmd)
 	newClient()
+	return nil
 }
 
 func newClient() {
@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/IBAX-io/ibax-cli/conf"
+	"github.com/IBAX-io/ibax-cli/packages/clierr"
+	"github.com/IBAX-io/ibax-cli/packages/keystore"
+	"github.com/IBAX-io/ibax-cli/packages/parameter"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	forceConfig bool
+
+	configShowCmd = &cobra.Command{
+		Use:   "show",
+		Short: "print config.yml with secrets redacted",
+		Long: `
+Request:
+	No parameters required
+
+Returns the settings of config.yml, private keys are replaced by ******.
+References to environment variables like ${OPERATOR_KEY} are printed as written.
+`,
+		Args:       cobra.NoArgs,
+		RunE:       configShow,
+		SuggestFor: []string{"show"},
+		Example:    "./ibax-cli config show\n./ibax-cli config show -o yaml",
+	}
+
+	configGetCmd = &cobra.Command{
+		Use:   "get [key]",
+		Short: "print a setting of config.yml",
+		Long: `
+Request:
+	key			(string) yaml key, nested keys are joined by dots: rpc_port, dir_path_conf.keys_dir, profiles.testnet.rpc_connect
+
+Returns the value of the key, private keys are redacted
+`,
+		Args:       cobra.ExactArgs(1),
+		RunE:       configGet,
+		SuggestFor: []string{"get"},
+		Example:    `./ibax-cli config get dir_path_conf.keys_dir -o raw`,
+	}
+
+	configSetCmd = &cobra.Command{
+		Use:   "set [key] [value]",
+		Short: "change a setting of config.yml",
+		Long: `
+Request:
+	key			(string) yaml key, nested keys are joined by dots
+	value		(string) new value, checked against the type of the setting
+
+Only the key is changed, the rest of the file is kept as written.
+A running console uses the new setting after "refresh".
+`,
+		Args:       cobra.ExactArgs(2),
+		RunE:       configSet,
+		SuggestFor: []string{"set"},
+		Example:    "./ibax-cli config set rpc_port 7079\n./ibax-cli config set profiles.testnet.keystore 2023-04-14T02-31-51.000000000Z-UTC-Keystore",
+	}
+
+	configValidateCmd = &cobra.Command{
+		Use:   "validate",
+		Short: "check config.yml",
+		Long: `
+Request:
+	No parameters required
+
+Checks the yaml schema, the cryptoer and hasher names, the node endpoint and the key files of every profile.
+Returns a json object of the problems found, the exit code is 3 if any error is found.
+Result:
+	{
+		"path": "str",					(string) config file
+		"valid": bool,					(boolean) false if any error is found
+		"problems": [
+			{
+				"level": "str",			(string) error | warning
+				"profile": "str",		(string) profile of the setting
+				"key": "str",			(string) yaml key of the setting
+				"message": "str"		(string) description
+			}
+		]
+	}
+`,
+		Args:       cobra.NoArgs,
+		RunE:       configValidate,
+		SuggestFor: []string{"validate"},
+		Example:    `./ibax-cli config validate`,
+	}
+
+	configPathCmd = &cobra.Command{
+		Use:        "path",
+		Short:      "print the path of config.yml",
+		Args:       cobra.NoArgs,
+		RunE:       printConfigPath,
+		SuggestFor: []string{"path"},
+		Example:    `./ibax-cli config path --path=./testnet/config.yml -o raw`,
+	}
+)
+
+func readDocument() (*conf.Document, error) {
+	doc, err := conf.ReadDocument(conf.Config.ConfigPath)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.Config, err, "loading config")
+	}
+	return doc, nil
+}
+
+func configShow(cmd *cobra.Command, params []string) error {
+	doc, err := readDocument()
+	if err != nil {
+		return err
+	}
+	m, err := doc.Map()
+	if err != nil {
+		return clierr.Wrap(clierr.Config, err, "loading config")
+	}
+	return printResult(conf.Redact("", m))
+}
+
+func configGet(cmd *cobra.Command, params []string) error {
+	args := parameter.New(params)
+	key, err := args.Set(0, true).String()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "key invalid")
+	}
+	doc, err := readDocument()
+	if err != nil {
+		return err
+	}
+	value, ok, err := doc.Get(key)
+	if err != nil {
+		return clierr.Wrap(clierr.Config, err, "loading config")
+	}
+	if !ok {
+		return clierr.New(clierr.NotFound, "key [%s] not found in %s", key, conf.Config.ConfigPath)
+	}
+	return printResult(conf.Redact(key, value))
+}
+
+func configSet(cmd *cobra.Command, params []string) error {
+	args := parameter.New(params)
+	key, err := args.Set(0, true).String()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "key invalid")
+	}
+	value, err := args.Set(1, false).String()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "value invalid")
+	}
+	doc, err := readDocument()
+	if err != nil {
+		return err
+	}
+	err = doc.Set(key, value)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "value invalid")
+	}
+	err = doc.Save(conf.Config.ConfigPath)
+	if err != nil {
+		return clierr.Wrap(clierr.Config, err, "Saving config")
+	}
+	if conf.IsSecret(key) && value != "" && !strings.HasPrefix(value, "$") {
+		log.Warnf("%s is saved in clear text, use a keystore or an environment variable like ${OPERATOR_KEY}", key)
+	}
+	log.Infof("%s is saved to %s", key, conf.Config.ConfigPath)
+	return nil
+}
+
+func printConfigPath(cmd *cobra.Command, params []string) error {
+	path, err := filepath.Abs(conf.Config.ConfigPath)
+	if err != nil {
+		return clierr.Wrap(clierr.Config, err, "config path invalid")
+	}
+	return printResult(path)
+}
+
+const (
+	problemError   = "error"
+	problemWarning = "warning"
+)
+
+type configProblem struct {
+	Level   string `json:"level"`
+	Profile string `json:"profile"`
+	Key     string `json:"key"`
+	Message string `json:"message"`
+}
+
+func configValidate(cmd *cobra.Command, params []string) error {
+	doc, err := readDocument()
+	if err != nil {
+		return err
+	}
+	type validation struct {
+		Path     string          `json:"path"`
+		Valid    bool            `json:"valid"`
+		Problems []configProblem `json:"problems"`
+	}
+	result := validation{Path: conf.Config.ConfigPath, Valid: true, Problems: []configProblem{}}
+	seen := make(map[string]bool)
+	report := func(level, profile, key, format string, a ...any) {
+		p := configProblem{Level: level, Profile: profile, Key: key, Message: fmt.Sprintf(format, a...)}
+		// settings inherited from the default profile are reported once
+		if seen[p.Key+p.Message] {
+			return
+		}
+		seen[p.Key+p.Message] = true
+		if level == problemError {
+			result.Valid = false
+		}
+		result.Problems = append(result.Problems, p)
+	}
+
+	c, err := doc.Config()
+	if err != nil {
+		report(problemError, "", "", "schema: %s", err)
+	}
+	for _, dir := range []struct{ key, path string }{
+		{"dir_path_conf.data_dir", c.DirPathConf.DataDir},
+		{"dir_path_conf.keys_dir", c.DirPathConf.KeysDir},
+	} {
+		if dir.path == "" {
+			continue
+		}
+		if fi, err := os.Stat(dir.path); err != nil || !fi.IsDir() {
+			report(problemWarning, conf.DefaultProfile, dir.key, "directory %s doesn't exist", dir.path)
+		}
+	}
+	if c.Profile != "" && !c.HasProfile(c.Profile) {
+		report(problemError, "", "profile", "active profile %s not found", c.Profile)
+	}
+	for _, name := range c.ProfileNames() {
+		p := c
+		if err := p.ApplyProfile(name); err != nil {
+			report(problemError, name, "profile", "%s", err)
+			continue
+		}
+		validateProfile(name, p, report)
+	}
+	err = printResult(result)
+	if err != nil {
+		return err
+	}
+	if !result.Valid {
+		return clierr.New(clierr.Config, "config %s invalid", conf.Config.ConfigPath)
+	}
+	return nil
+}
+
+func validateProfile(name string, p conf.GlobalConfig, report func(level, profile, key, format string, a ...any)) {
+	algo := keyAlgorithm{Cryptoer: p.Cryptoer, Hasher: p.Hasher}
+	if algo.Cryptoer == "" || algo.Hasher == "" {
+		report(problemWarning, name, "cryptoer", "cryptoer or hasher unset, %s is used", configAlgorithm())
+	} else if err := algo.validate(); err != nil {
+		report(problemError, name, "cryptoer", "%s", err)
+	}
+
+	u, err := url.Parse(p.RpcConnect)
+	switch {
+	case err != nil:
+		report(problemError, name, "rpc_connect", "%s", err)
+	case u.Scheme != "http" && u.Scheme != "https":
+		report(problemError, name, "rpc_connect", "endpoint %s must start with http:// or https://", p.RpcConnect)
+	case u.Hostname() == "":
+		report(problemError, name, "rpc_connect", "endpoint %s has no host", p.RpcConnect)
+	case u.Port() != "":
+		report(problemError, name, "rpc_connect", "endpoint %s must not hold the port, use rpc_port", p.RpcConnect)
+	}
+	if p.RpcPort < 1 || p.RpcPort > 65535 {
+		report(problemError, name, "rpc_port", "port %d invalid", p.RpcPort)
+	}
+
+	switch {
+	case p.PrivateKey != "":
+		key := os.ExpandEnv(p.PrivateKey)
+		if key == "" {
+			report(problemError, name, "private_key", "environment variable of %s unset", p.PrivateKey)
+		} else if _, err := hex.DecodeString(key); err != nil {
+			report(problemError, name, "private_key", "private key isn't hex: %s", err)
+		}
+	case p.Keystore != "":
+		filename := p.Keystore
+		if !filepath.IsAbs(filename) {
+			filename = filepath.Join(p.DirPathConf.KeysDir, filename)
+		}
+		if _, err := keystore.Load(filename); err != nil {
+			report(problemError, name, "keystore", "%s", err)
+		}
+	default:
+		report(problemWarning, name, "private_key", "no private_key or keystore, login isn't possible")
+	}
+	if p.PassphraseFile != "" {
+		if _, err := os.Stat(p.PassphraseFile); err != nil {
+			report(problemError, name, "passphrase_file", "%s", err)
+		}
+	}
+}
@@ -9,9 +9,9 @@ import (
 )
 
 var consoleCmd = &cobra.Command{
-	Use:    "console",
-	Short:  "IBAX console, command completion",
-	PreRun: loadConfigPre,
+	Use:     "console",
+	Short:   "IBAX console, command completion",
+	PreRunE: loadConfigPre,
 	Run: func(cmd *cobra.Command, args []string) {
 		consoleStart()
 	},
@@ -28,6 +28,10 @@ func consoleStart() {
 		log.Info("Console is running")
 		return
 	}
+	// the output format given to the console becomes the default of every command run in it
+	if flag := rootCmd.PersistentFlags().Lookup("output"); flag != nil {
+		flag.DefValue = outputFormat
+	}
 	fmt.Println("\nWelcome to the IBAX console!" +
 		"\nTo exit, press ctrl-d or type exit")
 	line := models.NewConsole()
@@ -1,15 +1,15 @@
 package cmd
 
 import (
-	"encoding/json"
-	"fmt"
+	"errors"
 	"github.com/IBAX-io/go-ibax-sdk/packages/request"
+	"github.com/IBAX-io/go-ibax/packages/types"
 	"github.com/IBAX-io/ibax-cli/models"
+	"github.com/IBAX-io/ibax-cli/packages/clierr"
 	"github.com/IBAX-io/ibax-cli/packages/parameter"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"os"
-	"strings"
 )
 
 // contract
@@ -45,8 +45,8 @@ Result:
 		SuggestFor: []string{"getContracts"},
 		Example:    "./ibax-cli getContracts [Limit] [Offset]",
 		Args:       cobra.RangeArgs(0, 2),
-		PreRun:     loginPre,
-		Run:        getContractsCmd,
+		PreRunE:    loginPre,
+		RunE:       getContractsCmd,
 	}
 
 	getContractInfo = &cobra.Command{
@@ -77,8 +77,8 @@ Returns information about the specified contract.
 		SuggestFor: []string{"getContractInfo"},
 		Example:    "./ibax-cli getContractInfo [ContractName]",
 		Args:       cobra.ExactArgs(1),
-		PreRun:     loginPre,
-		Run:        getContractInfoCmd,
+		PreRunE:    loginPre,
+		RunE:       getContractInfoCmd,
 	}
 
 	contractParamsFile string
@@ -89,10 +89,25 @@ Returns information about the specified contract.
 Request:
 	ContractName  		(string) call contract name
 	Params 				(json object,optional) contract params
-	Expedite			(string,optional) expedite unit: QIBAX
+	Expedite			(string,optional) expedite, IBAX or with unit: "0.5IBAX"
 
 call IBAX contract AND return transaction status information
 
+The params are checked against the contract fields before the call: required params must be given, unknown
+params are rejected and the values must match the field types (int, float, money, bool, string, bytes, address,
+array, map, file). Values are converted where nothing is lost, "10" is accepted for an int field.
+A money field takes the smallest unit, or an amount with the unit of the token of the login ecosystem.
+Every problem is reported together.
+
+String values starting with a tag are decoded, at any depth of the params:
+	"@file:path"			(file) {"Name", "MimeType", "Body"} of the file, the mime type is detected from the content
+	"@bytes:hex"			(bytes) bytes of the hex string, 0x prefix optional
+	"@base64:data"			(bytes) bytes of the standard base64 string
+	"@json:path"			(json) value of the json file, its tagged values are decoded too
+	"@@..."					(string) the string with one @ less
+An address field also takes an @alias of the address book, see "addressbook", other strings like "@home" are kept.
+Params named "<name>-file" hold the content of the file as a string. A file is limited to 10MiB and the params to 32MiB.
+
 Returns a json object transaction status information.
 Result:
 	{
@@ -101,68 +116,51 @@ Result:
 		"penalty": n,			(number) If transaction execution fails, (0: no penalty 1: penalty)
 		"err": ""				(string, optional) If the execution of the transaction fails, an error text message is returned.
 	}
-`,
-		Example:    "./ibax-cli callContract [ContractName] [Params] [Expedite]",
+` + moneyDoc + interactiveDoc + asyncDoc + dryRunDoc,
+		Example:    "./ibax-cli callContract [ContractName] [Params] [Expedite]\n./ibax-cli callContract -i @1TokensSend\n./ibax-cli callContract @1TokensSend '{\"Recipient\": \"0666-7782-xxxx-xxxx-3160\", \"Amount\": \"1.5IBAX\"}' '0.5IBAX'\n./ibax-cli callContract @1TokensSend '{\"Recipient\": \"0666-7782-xxxx-xxxx-3160\", \"Amount\": \"1000\"}' --dry-run",
 		SuggestFor: []string{"callContract"},
 		Args:       cobra.RangeArgs(1, 3),
-		PreRun:     loginPre,
-		Run:        callContractCmd,
+		PreRunE:    loginPre,
+		RunE:       callContractCmd,
 	}
 )
 
-func getContractsCmd(cmd *cobra.Command, params []string) {
+func getContractsCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	limit, err := args.Set(0, false).NumberInt64()
 	if err != nil {
-		log.Infof("limit invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "limit invalid")
 	}
 	offset, err := args.Set(1, false).NumberInt64()
 	if err != nil {
-		log.Infof("offset invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "offset invalid")
 	}
 
 	result, err := models.Client.GetContracts(limit, offset)
 	if err != nil {
-		log.Infof("Get Contracts Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "Get Contracts Failed")
 	}
 	if result == nil {
-		log.Info("Get Contracts Result Empty")
-		return
+		return clierr.New(clierr.NotFound, "Get Contracts Result Empty")
 	}
-	str, err := json.MarshalIndent(*result, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
-	}
-	fmt.Printf("\n%+v\n", string(str))
+	return printResult(*result)
 }
 
-func getContractInfoCmd(cmd *cobra.Command, params []string) {
+func getContractInfoCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	contractName, err := args.Set(0, true).String()
 	if err != nil {
-		log.Infof("ContractName invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "ContractName invalid")
 	}
 
 	result, err := models.Client.GetContract(contractName)
 	if err != nil {
-		log.Infof("Get GetContract Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "Get GetContract Failed")
 	}
 	if result == nil {
-		log.Info("Get GetContract Result Empty")
-		return
-	}
-	str, err := json.MarshalIndent(*result, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
+		return clierr.New(clierr.NotFound, "Get GetContract Result Empty")
 	}
-	fmt.Printf("\n%+v\n", string(str))
+	return printResult(*result)
 }
 
 func isFileType(arr []string) bool { //{"Name": string, "MimeType": string, "Body": bytes}
@@ -182,111 +180,107 @@ func isFileType(arr []string) bool { //{"Name": string, "MimeType": string, "Bod
 	return false
 }
 
-func callContractCmd(cmd *cobra.Command, params []string) {
-	if hasErrorContext(cmd) {
-		return
-	}
-
+func callContractCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	contractName, err := args.Set(0, true).String()
 	if err != nil {
-		log.Infof("ContractName invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "ContractName invalid")
 	}
 	contractParamsStr, err := args.Set(1, false).String()
 	if err != nil {
-		log.Infof("Params invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "Params invalid")
 	}
 
 	expedite, err := args.Set(2, false).String()
 	if err != nil {
-		log.Infof("Expedite invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "Expedite invalid")
 	}
 
-	if contractParamsFile != "" {
-		data, err := os.ReadFile(contractParamsFile)
-		if err != nil {
-			log.Infof("ReadFile Failed:%s", err.Error())
-			return
-		}
-		contractParamsStr = string(data)
+	if contractInteractive && (len(params) > 1 || contractParamsFile != "") {
+		return clierr.New(clierr.Argument, "Params can't be given with --interactive")
 	}
-	//models.Client.PrepareContractTx()
-	var contractParams request.MapParams
-	if contractParamsStr != "" {
-		err := json.Unmarshal([]byte(contractParamsStr), &contractParams)
-		if err != nil {
-			log.Infof("Params JSON Parsing Failed: %s", err.Error())
-			return
+	if err = checkAsyncFlags(); err != nil {
+		return err
+	}
+
+	contract, err := models.Client.GetContract(contractName)
+	if err != nil {
+		return clierr.Wrap(clierr.RPC, err, "Get GetContract Failed")
+	}
+	if contract == nil {
+		return clierr.New(clierr.NotFound, "Get GetContract Result Empty")
+	}
+	if contractInteractive {
+		contractParamsStr, expedite, err = promptContractCall(contract, expedite)
+		if errors.Is(err, models.ErrPromptCancelled) {
+			log.Info("Call Contract Cancelled")
+			return nil
 		}
-		const fileSuffix = "-file"
-		for k, v := range contractParams {
-			switch v.(type) {
-			case map[string]interface{}:
-				m := v.(map[string]interface{})
-				var keys []string
-				for mk, mv := range m {
-					if strings.HasSuffix(mk, fileSuffix) {
-						paramsName := mk[:len(fileSuffix)-1]
-						switch mv.(type) {
-						case string:
-							data, err := os.ReadFile(mv.(string))
-							if err != nil {
-								log.Infof("parse params file failed: %s", err.Error())
-								return
-							}
-							delete(m, mk)
-							m[paramsName] = string(data)
-							keys = append(keys, paramsName)
-						default:
-							log.Infof("params %s file type invalid", mk)
-						}
-					} else {
-						keys = append(keys, mk)
-					}
-				}
-				contractParams[k] = m
-				if isFileType(keys) {
-					for mk, mv := range m {
-						if mk == "Body" {
-							switch mv.(type) {
-							case string:
-								str := mv.(string)
-								m[mk] = []byte(str)
-							}
-						}
-					}
-					contractParams[k] = m
-				}
-			}
-			if strings.HasSuffix(k, fileSuffix) {
-				paramsName := k[:len(fileSuffix)-1]
-				data, err := os.ReadFile(contractParams.Get(k))
-				if err != nil {
-					log.Infof("parse params file failed: %s", err.Error())
-					return
-				}
-				delete(contractParams, k)
-				contractParams[paramsName] = string(data)
-			}
+		if err != nil {
+			return clierr.Wrap(clierr.Argument, err, "Prompt Params Failed")
 		}
 	}
+	expedite, err = parseExpedite(expedite)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "Expedite invalid")
+	}
+	contractParams, err := parseContractParams(contractParamsStr)
+	if err != nil {
+		return err
+	}
+	converted, err := convertContractParams(contract.Fields, contractParams)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "Params invalid")
+	}
+	if dryRun {
+		return contractDryRun(contract, converted, expedite)
+	}
+	if txAsync {
+		return sendTxAsync(&types.SmartTransaction{
+			Header:   &types.Header{ID: int(contract.ID)},
+			Expedite: expedite,
+			Params:   converted,
+		})
+	}
 
-	result, err := models.Client.AutoCallContract(contractName, &contractParams, expedite)
+	callParams := request.MapParams(converted)
+	result, err := models.Client.AutoCallContract(contractName, &callParams, expedite)
 	if err != nil {
-		log.Infof("Call Contract Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "Call Contract Failed")
 	}
 	if result == nil {
-		log.Info("Call Contract Result Empty")
-		return
+		return clierr.New(clierr.NotFound, "Call Contract Result Empty")
+	}
+	if txConfirmations >= 1 && result.Penalty == 0 && result.Err == "" {
+		return confirmTx(result.Hash, "Call Contract")
+	}
+	err = printResult(*result)
+	if err != nil {
+		return err
+	}
+	if result.Penalty == 1 || result.Err != "" {
+		return clierr.New(clierr.Rejected, "Call Contract Rejected: %s", result.Err)
+	}
+	return nil
+}
+
+// parseContractParams decodes the json params of callContract, read from the --file flag if given.
+// The tagged values and the "<name>-file" params are decoded by decodeParamTags
+func parseContractParams(contractParamsStr string) (request.MapParams, error) {
+	if contractParamsFile != "" {
+		data, err := os.ReadFile(contractParamsFile)
+		if err != nil {
+			return nil, clierr.Wrap(clierr.Argument, err, "ReadFile Failed")
+		}
+		contractParamsStr = string(data)
+	}
+	raw, err := decodeJSONParams(contractParamsStr)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.Argument, err, "Params JSON Parsing Failed")
 	}
-	str, err := json.MarshalIndent(*result, "", "    ")
+	err = decodeParamTags(raw)
 	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
+		return nil, clierr.Wrap(clierr.Argument, err, "Params invalid")
 	}
-	fmt.Printf("\n%+v\n", string(str))
+	return request.MapParams(raw), nil
 }
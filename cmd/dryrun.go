@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/IBAX-io/go-ibax-sdk/packages/request"
+	"github.com/IBAX-io/go-ibax-sdk/packages/response"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/IBAX-io/ibax-cli/models"
+	"github.com/IBAX-io/ibax-cli/packages/clierr"
+	"github.com/IBAX-io/ibax-cli/packages/txfile"
+	"github.com/shopspring/decimal"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var dryRun bool
+
+const dryRunDoc = `
+With --dry-run the call is checked and nothing is sent.
+Returns a json object of the transaction that would be sent.
+Result:
+	{
+		"contract": "str",			(string) Contract name with ecosystem prefix, for callContract
+		"type": "str",				(string) UTXO type, for callUtxo
+		"params": {},				(json object) Params after the tag decoding, bytes are written as 0x hex
+		"expedite": "str",			(string) Expedite
+		"account": "str",			(string) Sender account
+		"key_id": n,				(number) Sender key id
+		"ecosystem_id": n,			(number) Ecosystem id
+		"tx_size": n,				(number) Estimated transaction size in bytes
+		"fee": {					(json object) Estimated fee, null if the node doesn't give the fee parameters
+			"ecosystem": n,			(number) Ecosystem of the token paying the fee
+			"size_fee": "str",		(string) Fee of the transaction size, smallest unit
+			"expedite_fee": "str",	(string) Expedite fee, smallest unit
+			"total": "str",			(string) size_fee + expedite_fee
+			"balance": "str",		(string) Balance of the sender, smallest unit
+			"note": "str"			(string) What the estimate leaves out
+		},
+		"notes": []					(array) Why the fee isn't estimated
+	}
+`
+
+type dryRunFee struct {
+	Ecosystem   int64  `json:"ecosystem"`
+	SizeFee     string `json:"size_fee"`
+	ExpediteFee string `json:"expedite_fee"`
+	Total       string `json:"total"`
+	Balance     string `json:"balance"`
+	Note        string `json:"note"`
+}
+
+type dryRunResult struct {
+	Contract  string         `json:"contract,omitempty"`
+	Type      string         `json:"type,omitempty"`
+	Params    map[string]any `json:"params"`
+	Expedite  string         `json:"expedite"`
+	Account   string         `json:"account"`
+	KeyId     int64          `json:"key_id"`
+	Ecosystem int64          `json:"ecosystem_id"`
+	TxSize    int            `json:"tx_size"`
+	Fee       *dryRunFee     `json:"fee"`
+	Notes     []string       `json:"notes,omitempty"`
+}
+
+// newDryRun returns the dry run result of the transaction, the size is measured on the unsigned payload
+func newDryRun(tx *types.SmartTransaction, params map[string]any) (*dryRunResult, error) {
+	cnf := models.Client.GetConfig()
+	tx.Header.EcosystemID = cnf.Ecosystem
+	tx.Header.KeyID = cnf.KeyId
+	tx.Header.Time = time.Now().Unix()
+	tx.Header.PublicKey = cnf.PublicKey
+	payload, err := tx.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return &dryRunResult{
+		Params:    txfile.Display(params),
+		Expedite:  tx.Expedite,
+		Account:   cnf.Account,
+		KeyId:     cnf.KeyId,
+		Ecosystem: cnf.Ecosystem,
+		TxSize:    len(payload),
+	}, nil
+}
+
+// contractDryRun prints the call of the contract with the params already converted to the field types
+func contractDryRun(contract *response.GetContractResult, converted map[string]any, expedite string) error {
+	expediteFee, err := expediteAmount(expedite)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "Expedite invalid")
+	}
+	tx := &types.SmartTransaction{
+		Header:   &types.Header{ID: int(contract.ID)},
+		Expedite: expedite,
+		Params:   converted,
+	}
+	result, err := newDryRun(tx, converted)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "Params invalid")
+	}
+	result.Contract = contract.Name
+
+	tokenEcosystem, _ := strconv.ParseInt(contract.TokenID, 10, 64)
+	if tokenEcosystem <= 0 {
+		tokenEcosystem = consts.DefaultTokenEcosystem
+	}
+	result.Fee, err = contractFee(result, tokenEcosystem, expediteFee)
+	if err != nil {
+		result.Notes = append(result.Notes, fmt.Sprintf("fee not estimated: %s", err))
+	}
+	return printResult(result)
+}
+
+// contractFee estimates the storage and expedite fee of a contract call like the node:
+// price_tx_size * 10^digits * size / 1MiB, at least 1, and expedite * 10^digits, the node pays whole units
+func contractFee(result *dryRunResult, tokenEcosystem int64, expedite decimal.Decimal) (*dryRunFee, error) {
+	values, err := systemParamValues("price_tx_size")
+	if err != nil {
+		return nil, err
+	}
+	price, err := decimal.NewFromString(values["price_tx_size"])
+	if err != nil {
+		return nil, fmt.Errorf("price_tx_size: %w", err)
+	}
+	balance, err := models.Client.Balance(result.Account, tokenEcosystem)
+	if err != nil {
+		return nil, err
+	}
+	if balance == nil {
+		return nil, fmt.Errorf("balance of %s empty", result.Account)
+	}
+	digits := int32(balance.Digits)
+	sizeFee := price.Mul(decimal.New(1, digits)).Mul(decimal.NewFromInt(int64(result.TxSize))).Div(decimal.NewFromInt(consts.ChainSize)).Floor()
+	if sizeFee.LessThanOrEqual(decimal.Zero) {
+		sizeFee = decimal.New(1, 0)
+	}
+	expediteFee := expedite.Shift(digits)
+	return &dryRunFee{
+		Ecosystem:   tokenEcosystem,
+		SizeFee:     sizeFee.String(),
+		ExpediteFee: expediteFee.String(),
+		Total:       sizeFee.Add(expediteFee).String(),
+		Balance:     balance.Amount,
+		Note:        "the vm cost of the contract execution isn't included",
+	}, nil
+}
+
+func utxoDryRun(utxoTypeStr string, utxoType request.UtxoType, utxoParams request.MapParams, expedite string) error {
+	tx, err := newUtxoTx(utxoType, utxoParams, expedite)
+	if err != nil {
+		return err
+	}
+	expediteFee, _ := expediteAmount(expedite)
+	result, err := newDryRun(tx, map[string]any(utxoParams))
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "Params invalid")
+	}
+	result.Type = utxoTypeStr
+	if tx.UTXO == nil {
+		result.Notes = append(result.Notes, "fee not estimated: the node charges no fuel for transfers between the own accounts")
+		return printResult(result)
+	}
+	result.Fee, err = utxoFee(result, expediteFee)
+	if err != nil {
+		result.Notes = append(result.Notes, fmt.Sprintf("fee not estimated: %s", err))
+	}
+	return printResult(result)
+}
+
+// newUtxoTx checks the params of callUtxo and returns the transaction, the header is set by the sender.
+// An amount with unit is replaced by the amount of the smallest unit and an alias by its address in the params
+func newUtxoTx(utxoType request.UtxoType, utxoParams request.MapParams, expedite string) (*types.SmartTransaction, error) {
+	amount, err := parseMoney(fmt.Sprint(utxoParams["amount"]))
+	if err != nil {
+		return nil, clierr.Wrap(clierr.Argument, err, "Params invalid: amount")
+	}
+	if value, err := decimal.NewFromString(amount); err != nil || !value.IsInteger() || value.LessThanOrEqual(decimal.Zero) {
+		return nil, clierr.New(clierr.Argument, "Params invalid: amount [%s] must be a positive integer of the smallest unit", amount)
+	}
+	utxoParams["amount"] = amount
+	comment, _ := utxoParams["comment"].(string)
+	if _, err := expediteAmount(expedite); err != nil {
+		return nil, clierr.Wrap(clierr.Argument, err, "Expedite invalid")
+	}
+	tx := &types.SmartTransaction{Header: &types.Header{}, Expedite: expedite}
+	switch utxoType {
+	case request.TypeTransfer:
+		recipient, _ := utxoParams["recipient"].(string)
+		if keyId, ok := utxoParams["recipient"].(json.Number); ok {
+			recipient = keyId.String()
+		}
+		recipient, err = resolveAccount(recipient)
+		if err != nil {
+			return nil, clierr.Wrap(clierr.Argument, err, "Params invalid: recipient")
+		}
+		toId, err := parseKeyId(recipient)
+		if err != nil || toId == 0 {
+			return nil, clierr.New(clierr.Argument, "Params invalid: recipient [%s] invalid", recipient)
+		}
+		utxoParams["recipient"] = recipient
+		tx.UTXO = &types.UTXO{ToID: toId, Value: amount, Comment: comment}
+	case request.TypeContractToUTXO:
+		tx.TransferSelf = &types.TransferSelf{Value: amount, Source: "Account", Target: "UTXO"}
+	case request.TypeUTXOToContract:
+		tx.TransferSelf = &types.TransferSelf{Value: amount, Source: "UTXO", Target: "Account"}
+	}
+	return tx, nil
+}
+
+// utxoFee estimates the fee of an UTXO transfer like the node: fuel_rate / 10 * size and expedite * 10^12,
+// paid in ecosystem 1
+func utxoFee(result *dryRunResult, expedite decimal.Decimal) (*dryRunFee, error) {
+	rate, err := utxoFuelRate()
+	if err != nil {
+		return nil, err
+	}
+	balance, err := models.Client.Balance(result.Account, consts.DefaultTokenEcosystem)
+	if err != nil {
+		return nil, err
+	}
+	if balance == nil {
+		return nil, fmt.Errorf("balance of %s empty", result.Account)
+	}
+	sizeFee, expediteFee := utxoTxFee(rate, result.TxSize, expedite)
+	note := "fuel_rate / 10 per spent output is added"
+	if result.Ecosystem != consts.DefaultTokenEcosystem {
+		note += ", the fee of the token ecosystem isn't included"
+	}
+	return &dryRunFee{
+		Ecosystem:   consts.DefaultTokenEcosystem,
+		SizeFee:     sizeFee.String(),
+		ExpediteFee: expediteFee.String(),
+		Total:       sizeFee.Add(expediteFee).String(),
+		Balance:     balance.Utxo,
+		Note:        note,
+	}, nil
+}
+
+// utxoFuelRate returns the fuel_rate of ecosystem 1, zero when it isn't set
+func utxoFuelRate() (decimal.Decimal, error) {
+	values, err := systemParamValues("fuel_rate")
+	if err != nil {
+		return decimal.Zero, err
+	}
+	var rates [][]string
+	err = json.Unmarshal([]byte(values["fuel_rate"]), &rates)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("fuel_rate: %w", err)
+	}
+	rate := decimal.Zero
+	for _, item := range rates {
+		if len(item) == 2 && item[0] == strconv.Itoa(consts.DefaultTokenEcosystem) {
+			rate, err = decimal.NewFromString(item[1])
+			if err != nil {
+				return decimal.Zero, fmt.Errorf("fuel_rate: %w", err)
+			}
+		}
+	}
+	return rate, nil
+}
+
+// utxoTxFee returns the size fee and the expedite fee of an UTXO transfer of size bytes,
+// in the smallest unit of ecosystem 1
+func utxoTxFee(rate decimal.Decimal, size int, expedite decimal.Decimal) (sizeFee, expediteFee decimal.Decimal) {
+	sizeFee = rate.Div(decimal.NewFromInt(10)).Mul(decimal.NewFromInt(int64(size)))
+	return sizeFee, expedite.Shift(consts.MoneyDigits)
+}
+
+func expediteAmount(expedite string) (decimal.Decimal, error) {
+	if expedite == "" {
+		return decimal.Zero, nil
+	}
+	value, err := decimal.NewFromString(expedite)
+	if err != nil {
+		return value, err
+	}
+	if value.IsNegative() {
+		return value, fmt.Errorf("expedite [%s] must not be negative", expedite)
+	}
+	return value, nil
+}
+
+// systemParamValues returns the values of the platform parameters
+func systemParamValues(names ...string) (map[string]string, error) {
+	query := strings.Join(names, ",")
+	result, err := models.Client.SystemParams(query, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, fmt.Errorf("system params %s empty", query)
+	}
+	list, _ := (*result)["list"].([]any)
+	values := make(map[string]string, len(list))
+	for _, item := range list {
+		row, _ := item.(map[string]any)
+		name, _ := row["name"].(string)
+		value, _ := row["value"].(string)
+		values[name] = value
+	}
+	for _, name := range names {
+		if _, ok := values[name]; !ok {
+			return nil, fmt.Errorf("system param %s not found", name)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,293 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/ibax-cli/conf"
+	"github.com/IBAX-io/ibax-cli/models"
+	"github.com/IBAX-io/ibax-cli/packages/clierr"
+	"github.com/IBAX-io/ibax-cli/packages/hdwallet"
+	"github.com/IBAX-io/ibax-cli/packages/keystore"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+)
+
+var (
+	useMnemonic   bool
+	mnemonicWords int
+	mnemonicFile  string
+	derivePath    string
+	deriveCount   int
+
+	accountDeriveCmd = &cobra.Command{
+		Use:   "derive",
+		Short: "derive accounts of a mnemonic",
+		Long: `
+Request:
+	No parameters required
+
+Derives --count accounts from the mnemonic, starting at --path and increasing its last element.
+The mnemonic is read from --mnemonic-file (clear text or a keystore), otherwise prompted.
+The keys are saved in the keys directory like the keys of "account new".
+HD derivation is defined for the ECC_Secp256k1 cryptoer only.
+
+Returns a json array of the saved accounts, see "account new"
+Result:
+	[
+		{
+			"path": "str",					(string) Derivation path
+			"address": "str",				(string) Account Address: "xxxx-xxxx-xxxx-xxxx-xxxx"
+			"key_id": n,					(number) Key Id
+			"public_key": "str",			(string) Public Key
+			"private_key_file": "str",		(string) Path of the private key or keystore file
+			"public_key_file": "str",		(string) Path of the public key file
+			"encrypted": bool,				(boolean) true if the private key is saved in a keystore
+			"cryptoer": "str",				(string) Key algorithm
+			"hasher": "str"					(string) Hash algorithm of the address
+		}
+	]
+`,
+		PreRunE:    loadConfigPre,
+		RunE:       accountDerive,
+		SuggestFor: []string{"derive"},
+		Example:    "./ibax-cli account derive --path=\"m/44'/60'/0'/0/1\" --count=10 --mnemonic-file=./mnemonic",
+	}
+
+	accountRecoverCmd = &cobra.Command{
+		Use:   "recover",
+		Short: "rebuild the accounts of a mnemonic",
+		Long: `
+Request:
+	No parameters required
+
+Rebuilds the first --count accounts of the mnemonic, starting at --path.
+Accounts whose public key is already in the keys directory are skipped.
+The mnemonic is read from --mnemonic-file (clear text or a keystore), otherwise prompted.
+
+Returns a json array of the accounts like "account derive",
+the skipped accounts have no key files and "skipped": true.
+`,
+		PreRunE:    loadConfigPre,
+		RunE:       accountRecover,
+		SuggestFor: []string{"recover"},
+		Example:    `./ibax-cli account recover --count=5`,
+	}
+)
+
+const mnemonicWarning = `
+Warning
+The mnemonic restores all accounts derived from it. Write it down and keep it offline!
+You must NEVER share the mnemonic with anyone!
+`
+
+func init() {
+	accountNewCmd.Flags().BoolVar(&useMnemonic, "mnemonic", false, "derive the account from a new BIP-39 mnemonic")
+	accountNewCmd.Flags().IntVar(&mnemonicWords, "words", 12, "number of mnemonic words (12 | 24)")
+	accountNewCmd.Flags().StringVar(&mnemonicFile, "mnemonic-file", "", "save the mnemonic to the file instead of printing it, encrypted unless --plain is set")
+	accountNewCmd.Flags().StringVar(&derivePath, "path", hdwallet.DefaultPath, "BIP-32 derivation path")
+
+	for _, c := range []*cobra.Command{accountDeriveCmd, accountRecoverCmd} {
+		c.Flags().StringVar(&mnemonicFile, "mnemonic-file", "", "file holding the mnemonic, clear text or a keystore")
+		c.Flags().StringVar(&derivePath, "path", hdwallet.DefaultPath, "BIP-32 derivation path of the first account")
+		c.Flags().IntVar(&deriveCount, "count", 1, "number of accounts")
+		c.Flags().BoolVar(&plainKey, "plain", false, "save the private keys unencrypted")
+		c.Flags().StringVar(&conf.Config.DirPathConf.KeysDir, "keysDir", "", "Keys Directory")
+	}
+	addAlgorithmFlags(accountDeriveCmd, accountRecoverCmd)
+}
+
+// hdAlgorithm returns the algorithm of the command, BIP-32 is defined for secp256k1 keys only
+func hdAlgorithm() (keyAlgorithm, error) {
+	algo := commandAlgorithm()
+	if err := algo.validate(); err != nil {
+		return algo, err
+	}
+	if algo.Cryptoer != crypto.AsymAlgo_ECC_Secp256k1.String() {
+		return algo, fmt.Errorf("HD derivation needs cryptoer %s, got %s", crypto.AsymAlgo_ECC_Secp256k1, algo.Cryptoer)
+	}
+	return algo, nil
+}
+
+// readMnemonic reads the mnemonic from --mnemonic-file, or prompts for it
+func readMnemonic() (string, error) {
+	if mnemonicFile == "" {
+		return models.PasswordPrompt("Mnemonic: ")
+	}
+	data, err := os.ReadFile(mnemonicFile)
+	if err != nil {
+		return "", err
+	}
+	if !keystore.IsKeystore(data) {
+		return string(data), nil
+	}
+	phrase, _, err := unlockKeystore(mnemonicFile)
+	if err != nil {
+		return "", err
+	}
+	return string(phrase), nil
+}
+
+// saveMnemonic writes the mnemonic to the file, in a keystore unless the passphrase is empty
+func saveMnemonic(filename, mnemonic, passphrase string) error {
+	data := []byte(mnemonic)
+	if passphrase != "" {
+		ks, err := keystore.Encrypt(data, passphrase, "", 0, "")
+		if err != nil {
+			return err
+		}
+		data, err = ks.Marshal()
+		if err != nil {
+			return err
+		}
+	}
+	return createFile(filename, data)
+}
+
+// deriveAccounts saves count accounts of the seed starting at path, accounts with a public key in skip aren't saved
+func deriveAccounts(seed []byte, path hdwallet.Path, count int, passphrase string, algo keyAlgorithm, skip map[string]bool) ([]*savedAccount, error) {
+	var accounts []*savedAccount
+	err := withAlgorithm(algo, func() error {
+		for i := 0; i < count; i++ {
+			p, err := path.Next(uint32(i))
+			if err != nil {
+				return clierr.Wrap(clierr.Argument, err, "path invalid")
+			}
+			priv, err := hdwallet.Derive(seed, p)
+			if err != nil {
+				return clierr.Wrap(clierr.Unknown, err, "derive key failed")
+			}
+			pub, err := crypto.PrivateToPublic(priv)
+			if err != nil {
+				return clierr.Wrap(clierr.Unknown, err, "derive key failed")
+			}
+			if skip[crypto.PubToHex(pub)] {
+				account := newSavedAccount("", "", pub, algo)
+				account.Path, account.Skipped = p.String(), true
+				accounts = append(accounts, account)
+				continue
+			}
+			privateKeyName, publicKeyName, err := saveAccount(priv, pub, passphrase, algo)
+			if err != nil {
+				return clierr.Wrap(clierr.Unknown, err, "save account failed")
+			}
+			account := newSavedAccount(privateKeyName, publicKeyName, pub, algo)
+			account.Path = p.String()
+			accounts = append(accounts, account)
+		}
+		return nil
+	})
+	return accounts, err
+}
+
+// accountNewMnemonic creates a mnemonic and saves its account at --path
+func accountNewMnemonic(passphrase string) error {
+	algo, err := hdAlgorithm()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "algorithm invalid")
+	}
+	path, err := hdwallet.ParsePath(derivePath)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "path invalid")
+	}
+	mnemonic, err := hdwallet.NewMnemonic(mnemonicWords)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "generate mnemonic")
+	}
+	seed, err := hdwallet.Seed(mnemonic, "")
+	if err != nil {
+		return clierr.Wrap(clierr.Unknown, err, "generate mnemonic")
+	}
+	if mnemonicFile != "" {
+		err = saveMnemonic(mnemonicFile, mnemonic, passphrase)
+		if err != nil {
+			return clierr.Wrap(clierr.Unknown, err, "save mnemonic failed")
+		}
+	}
+	accounts, err := deriveAccounts(seed, path, 1, passphrase, algo, nil)
+	if err != nil {
+		return err
+	}
+	result := struct {
+		*savedAccount
+		Mnemonic     string `json:"mnemonic,omitempty"`
+		MnemonicFile string `json:"mnemonic_file,omitempty"`
+	}{savedAccount: accounts[0], MnemonicFile: mnemonicFile}
+	if mnemonicFile == "" {
+		result.Mnemonic = mnemonic
+	}
+	fmt.Fprintf(os.Stderr, "%s%s\n", newAccountWarning, mnemonicWarning)
+	return printResult(result)
+}
+
+// hdAccountsPre reads the algorithm, path, mnemonic and passphrase shared by derive and recover
+func hdAccountsPre() (algo keyAlgorithm, path hdwallet.Path, seed []byte, passphrase string, err error) {
+	algo, err = hdAlgorithm()
+	if err != nil {
+		return algo, nil, nil, "", clierr.Wrap(clierr.Argument, err, "algorithm invalid")
+	}
+	path, err = hdwallet.ParsePath(derivePath)
+	if err != nil {
+		return algo, nil, nil, "", clierr.Wrap(clierr.Argument, err, "path invalid")
+	}
+	if deriveCount < 1 {
+		return algo, nil, nil, "", clierr.New(clierr.Argument, "count %d invalid", deriveCount)
+	}
+	mnemonic, err := readMnemonic()
+	if err != nil {
+		return algo, nil, nil, "", clierr.Wrap(clierr.Argument, err, "read mnemonic failed")
+	}
+	seed, err = hdwallet.Seed(strings.TrimSpace(mnemonic), "")
+	if err != nil {
+		return algo, nil, nil, "", clierr.Wrap(clierr.Argument, err, "read mnemonic failed")
+	}
+	if !plainKey {
+		passphrase, err = models.NewPassphrase("Passphrase: ")
+		if err != nil {
+			return algo, nil, nil, "", clierr.Wrap(clierr.Argument, err, "passphrase invalid")
+		}
+	}
+	return algo, path, seed, passphrase, nil
+}
+
+func accountDerive(cmd *cobra.Command, params []string) error {
+	err := cobra.NoArgs(cmd, params)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "no parameters required")
+	}
+	algo, path, seed, passphrase, err := hdAccountsPre()
+	if err != nil {
+		return err
+	}
+	accounts, err := deriveAccounts(seed, path, deriveCount, passphrase, algo, nil)
+	if err != nil {
+		return err
+	}
+	return printResult(accounts)
+}
+
+func accountRecover(cmd *cobra.Command, params []string) error {
+	err := cobra.NoArgs(cmd, params)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "no parameters required")
+	}
+	algo, path, seed, passphrase, err := hdAccountsPre()
+	if err != nil {
+		return err
+	}
+	skip := make(map[string]bool)
+	list, err := scanKeysDir(conf.Config.DirPathConf.KeysDir, algo)
+	if err != nil && !os.IsNotExist(err) {
+		return clierr.Wrap(clierr.Config, err, "get keys dir failed")
+	}
+	for _, info := range list {
+		if info.Status == accountOk || info.Status == accountOrphanedPrivateKey {
+			skip[info.PublicKey] = true
+		}
+	}
+	accounts, err := deriveAccounts(seed, path, deriveCount, passphrase, algo, skip)
+	if err != nil {
+		return err
+	}
+	return printResult(accounts)
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"encoding/json"
+	"github.com/IBAX-io/go-ibax-sdk/packages/response"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/IBAX-io/ibax-cli/models"
+	"github.com/IBAX-io/ibax-cli/packages/clierr"
+	"github.com/IBAX-io/ibax-cli/packages/parameter"
+	"github.com/spf13/cobra"
+	"strconv"
+)
+
+// historyDefaultBlocks is the number of blocks scanned without --from
+const historyDefaultBlocks = 1000
+
+var (
+	historyFrom int64
+	historyTo   string
+
+	accountHistoryCmd = &cobra.Command{
+		Use:   "history [Account]",
+		Short: "list the transactions of an account",
+		Long: `
+Request:
+	Account			(string) Account Address: "xxxx-xxxx-xxxx-xxxx-xxxx", @alias or key id
+	--from			(number,optional) first block, default the latest 1000 blocks
+	--to			(string,optional) last block or latest, default latest
+	--no-cache		(bool,optional) request the blocks from the node instead of the block cache
+
+Scans the blocks 100 at a time with detailedBlocks for the transactions signed by the account
+and the utxo transfers to it. The blocks are read from the block cache when they are cached, see "cache",
+so that the next scans request only the new blocks.
+
+Returns a json array of the transactions in block order
+Result:
+	[
+		{
+			"block_id": n,				(number) block id
+			"hash": "str",				(string) transaction hash
+			"contract_name": "str",		(string) contract name, empty for utxo transactions
+			"params": {},				(object) contract params, {"UTXO": {"ToID", "Value", "Comment"}} for utxo transfers
+			"account": "str",			(string) signer address
+			"time": "str",				(string) ISO-8601 time in UTC
+			"type": "str",				(string) transaction type
+			"size": "str",				(string) size
+			"direction": "str"			(string) out: signed by the account || in: utxo transfer to the account
+		}
+	]
+`,
+		Args:       cobra.ExactArgs(1),
+		PreRunE:    loadConfigPre,
+		RunE:       accountHistory,
+		SuggestFor: []string{"history"},
+		Example:    "./ibax-cli account history 0666-7782-xxxx-xxxx-3160 --from=1000 --to=2000\n./ibax-cli account history @treasury -o table",
+	}
+)
+
+func init() {
+	accountHistoryCmd.Flags().Int64Var(&historyFrom, "from", 0, "first block (default the latest 1000 blocks)")
+	accountHistoryCmd.Flags().StringVar(&historyTo, "to", "latest", "last block or latest")
+	accountHistoryCmd.Flags().BoolVar(&noBlockCache, "no-cache", false, "request the blocks from the node instead of the block cache")
+}
+
+type historyView struct {
+	*txView
+	Direction string `json:"direction"`
+}
+
+// utxoToId returns the recipient key id of the params of a utxo transfer
+func utxoToId(params map[string]any) (int64, bool) {
+	utxo, ok := params["UTXO"].(map[string]any)
+	if !ok {
+		return 0, false
+	}
+	// the params are decoded with json.Number by models.DetailedBlocks and the block cache
+	switch v := utxo["ToID"].(type) {
+	case json.Number:
+		id, err := v.Int64()
+		return id, err == nil
+	case string:
+		id, err := strconv.ParseInt(v, 10, 64)
+		return id, err == nil
+	}
+	return 0, false
+}
+
+// historyDirection returns the direction of the transaction for the account, empty when it doesn't concern it
+func historyDirection(tx *txView, keyId int64) string {
+	if tx.keyId == keyId {
+		return "out"
+	}
+	if tx.txType != types.UtxoTxType {
+		return ""
+	}
+	if to, ok := utxoToId(tx.Params); ok && to == keyId {
+		return "in"
+	}
+	return ""
+}
+
+func accountHistory(cmd *cobra.Command, params []string) error {
+	args := parameter.New(params)
+	account, err := args.Set(0, true).String()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "account invalid")
+	}
+	keyId, err := parseKeyId(account)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "account invalid")
+	}
+	maxBlock, err := models.Client.GetMaxBlockID()
+	if err != nil {
+		return clierr.Wrap(clierr.RPC, err, "Get Max Block Failed")
+	}
+	to := maxBlock
+	if historyTo != "latest" {
+		to, err = strconv.ParseInt(historyTo, 10, 64)
+		if err != nil || to < 1 {
+			return clierr.New(clierr.Argument, "to [%s] invalid, a block id or latest", historyTo)
+		}
+		if to > maxBlock {
+			to = maxBlock
+		}
+	}
+	from := historyFrom
+	if from == 0 {
+		from = to - historyDefaultBlocks + 1
+		if from < 1 {
+			from = 1
+		}
+	}
+	if from < 1 || from > to {
+		return clierr.New(clierr.Argument, "blocks %d..%d empty, the latest block is %d", from, to, maxBlock)
+	}
+
+	list := make([]*historyView, 0)
+	err = scanBlocks(from, to, func(info *response.BlockDetailedInfo) error {
+		for _, tx := range newTxViews(info) {
+			if direction := historyDirection(tx, keyId); direction != "" {
+				list = append(list, &historyView{txView: tx, Direction: direction})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return clierr.Wrap(clierr.RPC, err, "Scan Blocks Failed")
+	}
+	return printResult(list)
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/IBAX-io/go-ibax-sdk/packages/response"
+	"github.com/IBAX-io/ibax-cli/models"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+)
+
+var contractInteractive bool
+
+const interactiveDoc = `
+With -i (--interactive) only the ContractName is given. The params are prompted field by field with the field
+type, file fields read a path (tab completes the file names), the other answers accept the param tags. A summary is shown and the call is sent after
+confirmation. The call is printed as a one-line command, in the console it's added to the history to be replayed.
+`
+
+// promptContractCall prompts the params and the expedite of the contract call.
+// It returns the params json and the expedite, or models.ErrPromptCancelled if the call isn't confirmed
+func promptContractCall(contract *response.GetContractResult, expedite string) (string, string, error) {
+	p := models.NewPrompter()
+	defer p.Close()
+
+	fmt.Printf("Contract %s, empty answers leave the optional params out\n", contract.Name)
+	values := make(map[string]any, len(contract.Fields))
+	for _, field := range contract.Fields {
+		value, err := promptField(p, field)
+		if err != nil {
+			return "", "", err
+		}
+		if value != nil {
+			values[field.Name] = value
+		}
+	}
+	for expedite == "" {
+		text, err := p.Prompt("Expedite (optional): ")
+		if err != nil {
+			return "", "", err
+		}
+		if _, err = parseExpedite(text); err != nil {
+			fmt.Printf("expedite invalid: %s\n", err)
+			continue
+		}
+		if text == "" {
+			break
+		}
+		expedite = text
+	}
+
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(values); err != nil {
+		return "", "", err
+	}
+	paramsStr := strings.TrimSpace(buf.String())
+	command := strings.TrimSpace(strings.Join([]string{"callContract", contract.Name, paramsStr, expedite}, " "))
+	if dryRun {
+		command += " --dry-run"
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "\nContract:\t%s\n", contract.Name)
+	for _, field := range contract.Fields {
+		if value, ok := values[field.Name]; ok {
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", field.Name, field.Type, summaryValue(value))
+		}
+	}
+	fmt.Fprintf(w, "Expedite:\t%s\n", expedite)
+	fmt.Fprintf(w, "Account:\t%s\n", models.Client.GetConfig().Account)
+	w.Flush()
+	fmt.Printf("\n./ibax-cli %s\n\n", shellQuote(command))
+
+	question := "Send the transaction? [y/N]: "
+	if dryRun {
+		question = "Check the transaction? [y/N]: "
+	}
+	answer, err := p.Prompt(question)
+	if err != nil {
+		return "", "", err
+	}
+	if !strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "yes") {
+		return "", "", models.ErrPromptCancelled
+	}
+	// only a confirmed call is kept in the history, it can be run again without the prompts
+	p.AppendHistory(command)
+	return paramsStr, expedite, nil
+}
+
+// promptField prompts the field until a valid value is given, nil is returned for an omitted optional field
+func promptField(p *models.Prompter, field response.Field) (any, error) {
+	need := "required"
+	if field.Optional {
+		need = "optional"
+	}
+	label := fmt.Sprintf("%s (%s, %s): ", field.Name, field.Type, need)
+	if field.Type == "file" {
+		label = fmt.Sprintf("%s (file path, %s): ", field.Name, need)
+	}
+	for {
+		var (
+			text string
+			err  error
+		)
+		if field.Type == "file" {
+			text, err = p.PromptPath(label)
+		} else {
+			text, err = p.Prompt(label)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if text == "" {
+			if field.Optional {
+				return nil, nil
+			}
+			fmt.Printf("%s is required\n", field.Name)
+			continue
+		}
+		value, err := fieldAnswer(field, text)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		return value, nil
+	}
+}
+
+// fieldAnswer returns the json value of the answer, checked against the field type.
+// A file path is given as @file:path, the file is read when the params are parsed
+func fieldAnswer(field response.Field, text string) (any, error) {
+	var value any = text
+	switch field.Type {
+	case "file":
+		path, err := filepath.Abs(text)
+		if err != nil {
+			return nil, err
+		}
+		value = tagFile + path
+	case "array", "map":
+		dec := json.NewDecoder(strings.NewReader(text))
+		dec.UseNumber()
+		if err := dec.Decode(&value); err != nil {
+			return nil, fmt.Errorf("param %s (%s): json expected: %w", field.Name, field.Type, err)
+		}
+	}
+	// the tags are decoded on a copy, the answer keeps them for the command
+	decoded, err := decodeParamValue(field.Name, copyValue(value))
+	if err != nil {
+		return nil, err
+	}
+	converted, err := convertFieldValue(field.Type, decoded)
+	if err != nil {
+		return nil, fmt.Errorf("param %s (%s): %w", field.Name, field.Type, err)
+	}
+	switch field.Type {
+	case "int", "float", "bool":
+		if _, ok := value.(string); ok && decoded == value {
+			return converted, nil
+		}
+	}
+	return value, nil
+}
+
+// copyValue returns a copy of the json value v, its maps and arrays aren't shared with v
+func copyValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(val))
+		for k, item := range val {
+			m[k] = copyValue(item)
+		}
+		return m
+	case []any:
+		list := make([]any, len(val))
+		for i, item := range val {
+			list[i] = copyValue(item)
+		}
+		return list
+	}
+	return v
+}
+
+func summaryValue(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+// shellQuote quotes the json params of the command for a shell
+func shellQuote(command string) string {
+	i := strings.Index(command, "{")
+	j := strings.LastIndex(command, "}")
+	if i < 0 || j < i {
+		return command
+	}
+	params := command[i : j+1]
+	return command[:i] + "'" + strings.ReplaceAll(params, "'", `'\''`) + "'" + command[j+1:]
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"github.com/IBAX-io/go-ibax-sdk/packages/response"
+	"reflect"
+	"testing"
+)
+
+func TestFieldAnswer(t *testing.T) {
+	// the tags of the answer are checked but kept for the command
+	field := response.Field{Name: "Data", Type: "map"}
+	value, err := fieldAnswer(field, `{"Key": "@bytes:0x0a0b", "List": ["@bytes:ff"]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{"Key": "@bytes:0x0a0b", "List": []any{"@bytes:ff"}}
+	if !reflect.DeepEqual(value, want) {
+		t.Errorf("answer = %#v, want %#v", value, want)
+	}
+	if _, err = fieldAnswer(field, `{"Key": "@bytes:zz"}`); err == nil {
+		t.Error("answer with an invalid tag accepted")
+	}
+
+	if value, err = fieldAnswer(response.Field{Name: "Amount", Type: "int"}, "42"); err != nil || value != int64(42) {
+		t.Errorf("int answer = %#v, %v, want 42", value, err)
+	}
+}
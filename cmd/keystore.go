@@ -0,0 +1,347 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/IBAX-io/go-ibax-sdk/packages/pkg/converter"
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/ibax-cli/conf"
+	"github.com/IBAX-io/ibax-cli/models"
+	"github.com/IBAX-io/ibax-cli/packages/clierr"
+	"github.com/IBAX-io/ibax-cli/packages/consts"
+	"github.com/IBAX-io/ibax-cli/packages/keystore"
+	"github.com/IBAX-io/ibax-cli/packages/parameter"
+	"github.com/spf13/cobra"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	importKeyFile    string
+	accountImportCmd = &cobra.Command{
+		Use:   "import [PrivateKey]",
+		Short: "import a private key into an encrypted keystore",
+		Long: `
+Request:
+	PrivateKey			(string,optional) hex private key, or use the --file flag
+
+The file given by --file holds a hex private key or a keystore, a keystore is unlocked with its passphrase.
+The key is encrypted with a new passphrase and saved in the keys directory.
+The algorithm tag of a keystore or a tagged key file is kept, otherwise --cryptoer and --hasher or the config apply.
+
+Returns the saved account like "account new"
+Result:
+	{
+		"address": "str",				(string) Account Address: "xxxx-xxxx-xxxx-xxxx-xxxx"
+		"key_id": n,					(number) Key Id
+		"public_key": "str",			(string) Public Key
+		"private_key_file": "str",		(string) Path of the keystore file
+		"public_key_file": "str",		(string) Path of the public key file
+		"encrypted": bool,				(boolean) true
+		"cryptoer": "str",				(string) Key algorithm
+		"hasher": "str"					(string) Hash algorithm of the address
+	}
+`,
+		PreRunE:    loadConfigPre,
+		RunE:       accountImport,
+		Args:       cobra.MaximumNArgs(1),
+		SuggestFor: []string{"import"},
+		Example:    `./ibax-cli account import --file=./PrivateKey`,
+	}
+
+	exportKeyFile    string
+	accountExportCmd = &cobra.Command{
+		Use:   "export [Keystore]",
+		Short: "print or save the private key of a keystore",
+		Long: `
+Request:
+	Keystore			(string) keystore file, absolute or in the keys directory
+
+Returns a json object for the unlocked account, or writes the hex private key to the --file flag
+Result:
+	{
+		"address": "str",			(string) Account Address: "xxxx-xxxx-xxxx-xxxx-xxxx"
+		"key_id": n,				(number) Key Id
+		"private_key": "str",		(string) hex private key
+		"cryptoer": "str",			(string,optional) Key algorithm of the keystore
+		"hasher": "str"				(string,optional) Hash algorithm of the keystore
+	}
+With --file:
+	{
+		"private_key_file": "str"	(string) Path of the private key file, the warning is written to stderr
+	}
+`,
+		PreRunE:    loadConfigPre,
+		RunE:       accountExport,
+		Args:       cobra.ExactArgs(1),
+		SuggestFor: []string{"export"},
+		Example:    `./ibax-cli account export 2023-04-14T02-31-51.000000000Z-UTC-Keystore`,
+	}
+
+	passwdNewFile    string
+	accountPasswdCmd = &cobra.Command{
+		Use:   "passwd [Keystore]",
+		Short: "change the passphrase of a keystore",
+		Long: `
+Request:
+	Keystore			(string) keystore file, absolute or in the keys directory
+	--new-passphrase-file	(string,optional) file holding the new passphrase
+
+The old passphrase is read like the one of the login, from the IBAX_PASSPHRASE environment variable or the
+passphrase_file config. The new one is read from the IBAX_NEW_PASSPHRASE environment variable or
+--new-passphrase-file, otherwise both are prompted.
+
+Result:
+	{
+		"keystore": "str"			(string) Path of the changed keystore
+	}
+`,
+		PreRunE:    loadConfigPre,
+		RunE:       accountPasswd,
+		Args:       cobra.ExactArgs(1),
+		SuggestFor: []string{"passwd"},
+		Example:    "./ibax-cli account passwd 2023-04-14T02-31-51.000000000Z-UTC-Keystore\nIBAX_PASSPHRASE=old IBAX_NEW_PASSPHRASE=new ./ibax-cli account passwd 2023-04-14T02-31-51.000000000Z-UTC-Keystore",
+	}
+)
+
+const exportWarning = `
+Warning
+The private key is written in clear text. The key controls access to your funds!
+`
+
+func init() {
+	accountImportCmd.Flags().StringVarP(&importKeyFile, "file", "f", "", "file holding the hex private key or a keystore")
+	accountExportCmd.Flags().StringVarP(&exportKeyFile, "file", "f", "", "save the hex private key to the file instead of printing it")
+	accountPasswdCmd.Flags().StringVar(&passwdNewFile, "new-passphrase-file", "", "file holding the new passphrase")
+	addAlgorithmFlags(accountImportCmd)
+}
+
+// keystorePath returns the keystore file name, relative names are looked up in the keys directory
+func keystorePath(name string) string {
+	if filepath.IsAbs(name) {
+		return name
+	}
+	if _, err := os.Stat(name); err == nil {
+		return name
+	}
+	return filepath.Join(conf.Config.DirPathConf.KeysDir, name)
+}
+
+// unlockKeystore prompts for the passphrase of the keystore and returns its private key
+func unlockKeystore(name string) ([]byte, *keystore.Keystore, error) {
+	filename := keystorePath(name)
+	ks, err := keystore.Load(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	passphrase, err := models.ReadPassphrase(fmt.Sprintf("Passphrase for %s: ", filepath.Base(filename)))
+	if err != nil {
+		return nil, nil, err
+	}
+	privateKey, err := ks.Decrypt(passphrase)
+	if err != nil {
+		return nil, nil, err
+	}
+	return privateKey, ks, nil
+}
+
+// keystoreAlgorithm returns the algorithm tag of the keystore, ok is false for keystores of older versions
+func keystoreAlgorithm(ks *keystore.Keystore) (a keyAlgorithm, ok bool, err error) {
+	if ks.Cryptoer == "" && ks.Hasher == "" {
+		return a, false, nil
+	}
+	a = keyAlgorithm{Cryptoer: ks.Cryptoer, Hasher: ks.Hasher}
+	return a, true, a.validate()
+}
+
+// saveAccount writes the key pair to the keys directory, the crypto package must be initialized with algo.
+// The private key is saved in a keystore, or in clear hex with an algorithm tag file if the passphrase is empty
+func saveAccount(priv, pub []byte, passphrase string, algo keyAlgorithm) (privateKeyName, publicKeyName string, err error) {
+	nowTimeStr := toISO8601(time.Now())
+	suffix := consts.PrivateKeyFilename
+	data := []byte(hex.EncodeToString(priv))
+	if passphrase != "" {
+		keyId := crypto.Address(pub)
+		ks, err := keystore.Encrypt(priv, passphrase, crypto.PubToHex(pub), keyId, converter.AddressToString(keyId))
+		if err != nil {
+			return "", "", err
+		}
+		ks.Cryptoer, ks.Hasher = algo.Cryptoer, algo.Hasher
+		data, err = ks.Marshal()
+		if err != nil {
+			return "", "", err
+		}
+		suffix = consts.KeystoreFilename
+	}
+	privateKeyName = filepath.Join(conf.Config.DirPathConf.KeysDir, fmt.Sprintf("%s-UTC-%s", nowTimeStr, suffix))
+	publicKeyName = filepath.Join(conf.Config.DirPathConf.KeysDir, fmt.Sprintf("%s-UTC-%s", nowTimeStr, consts.PublicKeyFilename))
+
+	err = createFile(privateKeyName, data)
+	if err != nil {
+		return "", "", fmt.Errorf("creating private key %s: %w", privateKeyName, err)
+	}
+	err = createFile(publicKeyName, []byte(crypto.PubToHex(pub)))
+	if err != nil {
+		return "", "", fmt.Errorf("creating public key %s: %w", publicKeyName, err)
+	}
+	if passphrase == "" {
+		tag, err := json.Marshal(algo)
+		if err != nil {
+			return "", "", err
+		}
+		algorithmName := algorithmFilename(privateKeyName)
+		err = createFile(algorithmName, tag)
+		if err != nil {
+			return "", "", fmt.Errorf("creating algorithm tag %s: %w", algorithmName, err)
+		}
+	}
+	return
+}
+
+func accountImport(cmd *cobra.Command, params []string) error {
+	args := parameter.New(params)
+	privateKeyStr, err := args.Set(0, false).String()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "private key invalid")
+	}
+	var (
+		priv   []byte
+		algo   = commandAlgorithm()
+		tagged bool
+	)
+	if importKeyFile != "" {
+		data, err := os.ReadFile(importKeyFile)
+		if err != nil {
+			return clierr.Wrap(clierr.Argument, err, "ReadFile Failed")
+		}
+		var tag keyAlgorithm
+		if keystore.IsKeystore(data) {
+			var ks *keystore.Keystore
+			priv, ks, err = unlockKeystore(importKeyFile)
+			if err != nil {
+				return clierr.Wrap(clierr.Auth, err, "unlock keystore failed")
+			}
+			tag, tagged, err = keystoreAlgorithm(ks)
+		} else {
+			privateKeyStr = string(data)
+			tag, tagged, err = readKeyAlgorithm(algorithmFilename(importKeyFile))
+		}
+		if err != nil {
+			return clierr.Wrap(clierr.Argument, err, "algorithm tag invalid")
+		}
+		if tagged {
+			algo = tag
+		}
+	}
+	if priv == nil {
+		if privateKeyStr == "" {
+			return clierr.New(clierr.Argument, "private key or file must have one")
+		}
+		priv, err = hex.DecodeString(strings.TrimSpace(privateKeyStr))
+		if err != nil {
+			return clierr.Wrap(clierr.Argument, err, "private key decode failed")
+		}
+	}
+	if err := algo.validate(); err != nil {
+		return clierr.Wrap(clierr.Argument, err, "algorithm invalid")
+	}
+	passphrase, err := models.NewPassphrase("New passphrase: ")
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "passphrase invalid")
+	}
+	return withAlgorithm(algo, func() error {
+		pub, err := crypto.PrivateToPublic(priv)
+		if err != nil {
+			return clierr.Wrap(clierr.Argument, err, "private key invalid")
+		}
+		privateKeyName, publicKeyName, err := saveAccount(priv, pub, passphrase, algo)
+		if err != nil {
+			return clierr.Wrap(clierr.Unknown, err, "account import failed")
+		}
+		return printResult(newSavedAccount(privateKeyName, publicKeyName, pub, algo))
+	})
+}
+
+func accountExport(cmd *cobra.Command, params []string) error {
+	args := parameter.New(params)
+	name, err := args.Set(0, true).String()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "keystore invalid")
+	}
+	priv, ks, err := unlockKeystore(name)
+	if err != nil {
+		return clierr.Wrap(clierr.Auth, err, "unlock keystore failed")
+	}
+	if exportKeyFile != "" {
+		err = createFile(exportKeyFile, []byte(hex.EncodeToString(priv)))
+		if err != nil {
+			return clierr.Wrap(clierr.Unknown, err, "save private key failed")
+		}
+		if algorithmName := algorithmFilename(exportKeyFile); algorithmName != "" && ks.Cryptoer != "" {
+			tag, _ := json.Marshal(keyAlgorithm{Cryptoer: ks.Cryptoer, Hasher: ks.Hasher})
+			err = createFile(algorithmName, tag)
+			if err != nil {
+				return clierr.Wrap(clierr.Unknown, err, "save algorithm tag failed")
+			}
+		}
+		fmt.Fprint(os.Stderr, exportWarning)
+		return printResult(struct {
+			PrivateKeyFile string `json:"private_key_file"`
+		}{exportKeyFile})
+	}
+	type exportInfo struct {
+		Address    string `json:"address"`
+		KeyId      int64  `json:"key_id"`
+		PrivateKey string `json:"private_key"`
+		Cryptoer   string `json:"cryptoer,omitempty"`
+		Hasher     string `json:"hasher,omitempty"`
+	}
+	return printResult(exportInfo{
+		Address:    ks.Address,
+		KeyId:      ks.KeyId,
+		PrivateKey: hex.EncodeToString(priv),
+		Cryptoer:   ks.Cryptoer,
+		Hasher:     ks.Hasher,
+	})
+}
+
+func accountPasswd(cmd *cobra.Command, params []string) error {
+	args := parameter.New(params)
+	name, err := args.Set(0, true).String()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "keystore invalid")
+	}
+	priv, ks, err := unlockKeystore(name)
+	if err != nil {
+		return clierr.Wrap(clierr.Auth, err, "unlock keystore failed")
+	}
+	passphrase, err := models.ChangedPassphrase(passwdNewFile, "New passphrase: ")
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "passphrase invalid")
+	}
+	updated, err := keystore.Encrypt(priv, passphrase, ks.PublicKey, ks.KeyId, ks.Address)
+	if err != nil {
+		return clierr.Wrap(clierr.Unknown, err, "encrypt keystore failed")
+	}
+	updated.Cryptoer, updated.Hasher = ks.Cryptoer, ks.Hasher
+	data, err := updated.Marshal()
+	if err != nil {
+		return clierr.Wrap(clierr.Unknown, err, "encrypt keystore failed")
+	}
+	filename := keystorePath(name)
+	tmp := filename + ".tmp"
+	err = os.WriteFile(tmp, data, fileMode)
+	if err != nil {
+		return clierr.Wrap(clierr.Unknown, err, "save keystore failed")
+	}
+	err = os.Rename(tmp, filename)
+	if err != nil {
+		return clierr.Wrap(clierr.Unknown, err, "save keystore failed")
+	}
+	return printResult(struct {
+		Keystore string `json:"keystore"`
+	}{filename})
+}
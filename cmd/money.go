@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/ibax-cli/models"
+	"github.com/IBAX-io/ibax-cli/packages/money"
+	"strconv"
+)
+
+const moneyDoc = `
+Amounts and expedite may be given with a unit of the token: "1.5IBAX" is 1.5 tokens and "1500000000000QIBAX"
+is in the smallest unit, with the digits and token_symbol of the ecosystem. Without unit an amount is an integer
+of the smallest unit and the expedite in tokens (IBAX). Negative amounts are rejected.
+`
+
+// tokens caches the tokens of the ecosystems, they don't change
+var tokens = make(map[int64]money.Token)
+
+// ecosystemToken returns the symbol and digits of the token of the ecosystem
+func ecosystemToken(ecosystem int64) (money.Token, error) {
+	if token, ok := tokens[ecosystem]; ok {
+		return token, nil
+	}
+	result, err := models.Client.EcosystemInfo(ecosystem)
+	if err != nil {
+		return money.Token{}, err
+	}
+	if result == nil {
+		return money.Token{}, fmt.Errorf("ecosystem %d not found", ecosystem)
+	}
+	digits, err := strconv.ParseInt(fmt.Sprint((*result)["digits"]), 10, 32)
+	if err != nil {
+		return money.Token{}, fmt.Errorf("digits of ecosystem %d invalid", ecosystem)
+	}
+	symbol, _ := (*result)["token_symbol"].(string)
+	token := money.Token{Symbol: symbol, Digits: int32(digits)}
+	tokens[ecosystem] = token
+	return token, nil
+}
+
+// parseMoney returns the amount in the smallest unit of the token of the login ecosystem
+func parseMoney(amount string) (string, error) {
+	if !money.HasUnit(amount) {
+		return amount, nil
+	}
+	token, err := ecosystemToken(models.Client.GetConfig().Ecosystem)
+	if err != nil {
+		return "", err
+	}
+	value, err := token.Smallest(amount)
+	if err != nil {
+		return "", err
+	}
+	return value.String(), nil
+}
+
+// parseExpedite returns the expedite in IBAX, it is paid in the token of ecosystem 1
+func parseExpedite(expedite string) (string, error) {
+	if money.HasUnit(expedite) {
+		token, err := ecosystemToken(consts.DefaultTokenEcosystem)
+		if err != nil {
+			return "", err
+		}
+		value, err := token.Whole(expedite)
+		if err != nil {
+			return "", err
+		}
+		expedite = value.String()
+	}
+	if _, err := expediteAmount(expedite); err != nil {
+		return "", err
+	}
+	return expedite, nil
+}
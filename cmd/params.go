@@ -0,0 +1,389 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/IBAX-io/go-ibax-sdk/packages/pkg/converter"
+	"github.com/IBAX-io/go-ibax-sdk/packages/response"
+	"github.com/IBAX-io/ibax-cli/packages/money"
+	"github.com/shopspring/decimal"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// decodeJSONParams decodes json params keeping the numbers as written
+func decodeJSONParams(paramsStr string) (map[string]any, error) {
+	raw := make(map[string]any)
+	if paramsStr == "" {
+		return raw, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader([]byte(paramsStr)))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("params JSON parsing failed: %w", err)
+	}
+	return raw, nil
+}
+
+// convertContractParams checks the params against the contract fields and converts them to the field types.
+// Values are coerced where nothing is lost, "10" for an int field becomes 10.
+// Missing required params, unknown params and mismatched types are all reported in one error
+func convertContractParams(fields []response.Field, raw map[string]any) (map[string]any, error) {
+	var problems []error
+	known := make(map[string]bool, len(fields))
+	out := make(map[string]any, len(raw))
+	for _, field := range fields {
+		known[field.Name] = true
+		v, ok := raw[field.Name]
+		if !ok {
+			if !field.Optional {
+				problems = append(problems, fmt.Errorf("param %s (%s) is required", field.Name, field.Type))
+			}
+			continue
+		}
+		value, err := convertFieldValue(field.Type, v)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("param %s (%s): %w", field.Name, field.Type, err))
+			continue
+		}
+		out[field.Name] = value
+	}
+	var unknown []string
+	for name := range raw {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		problems = append(problems, fmt.Errorf("param %s isn't a field of the contract", name))
+	}
+	if len(problems) > 0 {
+		return nil, errors.Join(problems...)
+	}
+	return out, nil
+}
+
+// convertFieldValue converts the json value v to the contract field type
+func convertFieldValue(fieldType string, v any) (any, error) {
+	switch v.(type) {
+	case map[string]any:
+		if fieldType != "map" && fieldType != "file" {
+			return nil, errors.New("object given")
+		}
+	case []any:
+		if fieldType != "array" {
+			return nil, errors.New("array given")
+		}
+	case nil:
+		return nil, errors.New("null given")
+	}
+	text := fmt.Sprint(v)
+	switch fieldType {
+	case "int":
+		i, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("[%s] isn't an integer", text)
+		}
+		return i, nil
+	case "address":
+		if s, ok := v.(string); ok && (strings.Contains(s, "-") || isAlias(s)) {
+			return parseKeyId(s)
+		}
+		i, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("[%s] isn't an account address or a key id", text)
+		}
+		return i, nil
+	case "float":
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("[%s] isn't a number", text)
+		}
+		return f, nil
+	case "money":
+		amount := text
+		if money.HasUnit(text) {
+			var err error
+			if amount, err = parseMoney(text); err != nil {
+				return nil, err
+			}
+		}
+		// without unit the amount is in the smallest unit, which has no fractions
+		d, err := decimal.NewFromString(amount)
+		if err != nil || !d.IsInteger() {
+			return nil, fmt.Errorf("[%s] isn't an integer of the smallest unit, give tokens with a unit like 1.5IBAX", text)
+		}
+		if d.IsNegative() {
+			return nil, fmt.Errorf("[%s] is negative", text)
+		}
+		return d.String(), nil
+	case "bool":
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, fmt.Errorf("[%s] isn't true or false", text)
+		}
+		return b, nil
+	case "string":
+		return text, nil
+	case "bytes":
+		switch val := v.(type) {
+		case string:
+			return []byte(val), nil
+		case []byte:
+			return val, nil
+		}
+		return nil, fmt.Errorf("[%s] isn't a string", text)
+	case "file":
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf(`[%s] isn't an object {"Name", "MimeType", "Body"}`, text)
+		}
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		if !isFileType(keys) {
+			return nil, errors.New(`object {"Name", "MimeType", "Body"} expected`)
+		}
+		file := jsonValue(m).(map[string]any)
+		if body, ok := file["Body"].(string); ok {
+			file["Body"] = []byte(body)
+		}
+		return file, nil
+	case "array":
+		if _, ok := v.([]any); !ok {
+			return nil, fmt.Errorf("[%s] isn't an array", text)
+		}
+	case "map":
+		if _, ok := v.(map[string]any); !ok {
+			return nil, fmt.Errorf("[%s] isn't an object", text)
+		}
+	}
+	return jsonValue(v), nil
+}
+
+// jsonValue replaces the json numbers, integers become int64 and other numbers float64
+func jsonValue(v any) any {
+	switch val := v.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		f, _ := val.Float64()
+		return f
+	case map[string]any:
+		for k, item := range val {
+			val[k] = jsonValue(item)
+		}
+	case []any:
+		for i, item := range val {
+			val[i] = jsonValue(item)
+		}
+	}
+	return v
+}
+
+// parseKeyId accepts an account address, an alias of the address book or a key id
+func parseKeyId(account string) (int64, error) {
+	account, err := resolveAccount(account)
+	if err != nil {
+		return 0, err
+	}
+	if strings.Contains(account, "-") && len(account) > 20 {
+		keyId := converter.StringToAddress(account)
+		if keyId == 0 {
+			return 0, fmt.Errorf("account address [%s] invalid", account)
+		}
+		return keyId, nil
+	}
+	return strconv.ParseInt(account, 10, 64)
+}
+
+// Param tags, string values starting with a tag are decoded at any depth of the params:
+//
+//	@file:path     file object {"Name", "MimeType", "Body"}, the mime type is detected from the content
+//	@bytes:hex     bytes of the hex string, 0x prefix optional
+//	@base64:data   bytes of the standard base64 string
+//	@json:path     json value of the file, its tagged values are decoded too
+//
+// An @alias of the address book is resolved by the address fields of the contract, see convertFieldValue,
+// other strings like "@home" are kept.
+// A string starting with @@ is kept with one @ less, "@@file:x" is the string "@file:x".
+// Params named "<name>-file" are replaced by "<name>" holding the content of the file as a string,
+// and the string Body of a file object becomes bytes.
+const (
+	tagFile   = "@file:"
+	tagBytes  = "@bytes:"
+	tagBase64 = "@base64:"
+	tagJSON   = "@json:"
+
+	fileSuffix = "-file"
+
+	// maxParamFileSize limits a file read by @file:, @json: or "<name>-file"
+	maxParamFileSize = 10 << 20
+	// maxParamsSize limits the decoded bytes of all the params, the default max_tx_size of the node is 32MiB
+	maxParamsSize = 32 << 20
+	// maxParamDepth limits the nesting of the params, @json: files included
+	maxParamDepth = 32
+)
+
+// decodeParamTags decodes the tagged values of the params in place
+func decodeParamTags(params map[string]any) error {
+	d := &paramDecoder{}
+	_, err := d.decode("", params, 0)
+	return err
+}
+
+// decodeParamValue decodes the tagged values of the param name
+func decodeParamValue(name string, v any) (any, error) {
+	d := &paramDecoder{}
+	return d.decode(name, v, 0)
+}
+
+type paramDecoder struct {
+	size int64
+}
+
+func (d *paramDecoder) decode(path string, v any, depth int) (any, error) {
+	if depth > maxParamDepth {
+		return nil, fmt.Errorf("param %s nested deeper than %d", path, maxParamDepth)
+	}
+	switch val := v.(type) {
+	case string:
+		return d.decodeString(path, val, depth)
+	case []any:
+		for i, item := range val {
+			decoded, err := d.decode(fmt.Sprintf("%s[%d]", path, i), item, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			val[i] = decoded
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			item := val[k]
+			itemPath := joinParamPath(path, k)
+			if strings.HasSuffix(k, fileSuffix) {
+				filename, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("param %s: file name expected", itemPath)
+				}
+				data, err := d.readFile(itemPath, filename)
+				if err != nil {
+					return nil, err
+				}
+				delete(val, k)
+				val[strings.TrimSuffix(k, fileSuffix)] = string(data)
+				continue
+			}
+			decoded, err := d.decode(itemPath, item, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			val[k] = decoded
+		}
+		keys = keys[:0]
+		for k := range val {
+			keys = append(keys, k)
+		}
+		if body, ok := val["Body"].(string); ok && isFileType(keys) {
+			val["Body"] = []byte(body)
+		}
+	}
+	return v, nil
+}
+
+func (d *paramDecoder) decodeString(path, s string, depth int) (any, error) {
+	switch {
+	case strings.HasPrefix(s, "@@"):
+		return s[1:], nil
+	case strings.HasPrefix(s, tagFile):
+		filename := strings.TrimPrefix(s, tagFile)
+		data, err := d.readFile(path, filename)
+		if err != nil {
+			return nil, err
+		}
+		mimeType, _, err := getMimeType(filename)
+		if err != nil {
+			return nil, fmt.Errorf("param %s: %w", path, err)
+		}
+		return map[string]any{"Name": filepath.Base(filename), "MimeType": mimeType, "Body": data}, nil
+	case strings.HasPrefix(s, tagBytes):
+		text := strings.TrimPrefix(strings.TrimPrefix(s, tagBytes), "0x")
+		data, err := hex.DecodeString(text)
+		if err != nil {
+			return nil, fmt.Errorf("param %s: %s hex invalid: %w", path, tagBytes, err)
+		}
+		return data, d.add(path, len(data))
+	case strings.HasPrefix(s, tagBase64):
+		data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s, tagBase64))
+		if err != nil {
+			return nil, fmt.Errorf("param %s: %s data invalid: %w", path, tagBase64, err)
+		}
+		return data, d.add(path, len(data))
+	case strings.HasPrefix(s, tagJSON):
+		filename := strings.TrimPrefix(s, tagJSON)
+		data, err := d.readFile(path, filename)
+		if err != nil {
+			return nil, err
+		}
+		var value any
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err = dec.Decode(&value); err != nil {
+			return nil, fmt.Errorf("param %s: %s %s: %w", path, tagJSON, filename, err)
+		}
+		return d.decode(path, value, depth+1)
+	}
+	return s, d.add(path, len(s))
+}
+
+// readFile reads the file of the param within the size limits
+func (d *paramDecoder) readFile(path, filename string) ([]byte, error) {
+	if filename == "" {
+		return nil, fmt.Errorf("param %s: file name empty", path)
+	}
+	info, err := os.Stat(filename)
+	if err != nil {
+		return nil, fmt.Errorf("param %s: %w", path, err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("param %s: %s is a directory", path, filename)
+	}
+	if info.Size() > maxParamFileSize {
+		return nil, fmt.Errorf("param %s: file %s is larger than %d bytes", path, filename, maxParamFileSize)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("param %s: %w", path, err)
+	}
+	return data, d.add(path, len(data))
+}
+
+func (d *paramDecoder) add(path string, size int) error {
+	d.size += int64(size)
+	if d.size > maxParamsSize {
+		return fmt.Errorf("param %s: params are larger than %d bytes", path, maxParamsSize)
+	}
+	return nil
+}
+
+func joinParamPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
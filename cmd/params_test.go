@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"bytes"
+	"github.com/IBAX-io/go-ibax-sdk/packages/response"
+	"github.com/IBAX-io/ibax-cli/conf"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeParamFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func decodeParamsString(t *testing.T, paramsStr string) (map[string]any, error) {
+	t.Helper()
+	params, err := decodeJSONParams(paramsStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return params, decodeParamTags(params)
+}
+
+func TestDecodeParamTagsBytes(t *testing.T) {
+	params, err := decodeParamsString(t, `{"Hex": "@bytes:0x0a0b", "Plain": "@bytes:ff", "B64": "@base64:aGVsbG8="}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]byte{"Hex": {0x0a, 0x0b}, "Plain": {0xff}, "B64": []byte("hello")}
+	for name, data := range want {
+		got, ok := params[name].([]byte)
+		if !ok || !bytes.Equal(got, data) {
+			t.Errorf("%s = %#v, want %#v", name, params[name], data)
+		}
+	}
+}
+
+func TestDecodeParamTagsFile(t *testing.T) {
+	filename := writeParamFile(t, "note.txt", []byte("hello world"))
+	params, err := decodeParamsString(t, `{"Data": {"List": [{"Doc": "@file:`+filename+`"}]}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, ok := params["Data"].(map[string]any)["List"].([]any)[0].(map[string]any)["Doc"].(map[string]any)
+	if !ok {
+		t.Fatalf("nested file not decoded: %#v", params)
+	}
+	if file["Name"] != "note.txt" {
+		t.Errorf("Name = %v", file["Name"])
+	}
+	if mimeType, _ := file["MimeType"].(string); !strings.HasPrefix(mimeType, "text/plain") {
+		t.Errorf("MimeType = %v", file["MimeType"])
+	}
+	if body, _ := file["Body"].([]byte); string(body) != "hello world" {
+		t.Errorf("Body = %#v", file["Body"])
+	}
+}
+
+func TestDecodeParamTagsJSON(t *testing.T) {
+	inner := writeParamFile(t, "inner.json", []byte(`{"Key": "@bytes:01", "Count": 3}`))
+	outer := writeParamFile(t, "outer.json", []byte(`["@json:`+inner+`", "@@json:kept"]`))
+	params, err := decodeParamsString(t, `{"List": "@json:`+outer+`"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, ok := params["List"].([]any)
+	if !ok || len(list) != 2 {
+		t.Fatalf("List = %#v", params["List"])
+	}
+	item, _ := list[0].(map[string]any)
+	if key, _ := item["Key"].([]byte); !bytes.Equal(key, []byte{1}) {
+		t.Errorf("Key = %#v", item["Key"])
+	}
+	if list[1] != "@json:kept" {
+		t.Errorf("escaped string = %#v", list[1])
+	}
+}
+
+func TestDecodeParamTagsFileSuffix(t *testing.T) {
+	filename := writeParamFile(t, "body.txt", []byte("content"))
+	params, err := decodeParamsString(t, `{"Text-file": "`+filename+`", "Doc": {"Name": "a", "MimeType": "text/plain", "Body-file": "`+filename+`"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := params["Text-file"]; ok {
+		t.Error("Text-file not replaced")
+	}
+	if params["Text"] != "content" {
+		t.Errorf("Text = %#v", params["Text"])
+	}
+	doc := params["Doc"].(map[string]any)
+	if body, _ := doc["Body"].([]byte); string(body) != "content" {
+		t.Errorf("Body = %#v", doc["Body"])
+	}
+}
+
+func TestDecodeParamTagsPlainValues(t *testing.T) {
+	params, err := decodeParamsString(t, `{"Name": "@1TokensSend", "Amount": "10", "Flag": true, "Ratio": 1.5}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params["Name"] != "@1TokensSend" || params["Amount"] != "10" || params["Flag"] != true {
+		t.Errorf("plain values changed: %#v", params)
+	}
+}
+
+func TestDecodeParamTagsErrors(t *testing.T) {
+	dir := t.TempDir()
+	self := filepath.Join(dir, "self.json")
+	if err := os.WriteFile(self, []byte(`["@json:`+self+`"]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		params string
+		want   string
+	}{
+		{"bad hex", `{"A": {"B": "@bytes:xyz"}}`, "param A.B"},
+		{"bad base64", `{"A": ["@base64:!!"]}`, "param A[0]"},
+		{"missing file", `{"A": "@file:` + filepath.Join(dir, "missing") + `"}`, "param A"},
+		{"directory", `{"A": "@file:` + dir + `"}`, "is a directory"},
+		{"empty file name", `{"A": "@json:"}`, "file name empty"},
+		{"file suffix not a name", `{"A-file": 1}`, "file name expected"},
+		{"recursive json", `{"A": "@json:` + self + `"}`, "nested deeper"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeParamsString(t, tt.params)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeParamTagsSizeLimit(t *testing.T) {
+	big := writeParamFile(t, "big.bin", make([]byte, maxParamFileSize+1))
+	_, err := decodeParamsString(t, `{"A": "@file:`+big+`"}`)
+	if err == nil || !strings.Contains(err.Error(), "larger than") {
+		t.Errorf("file limit err = %v", err)
+	}
+
+	part := writeParamFile(t, "part.bin", make([]byte, maxParamFileSize))
+	var items []string
+	for i := 0; i*maxParamFileSize <= maxParamsSize; i++ {
+		items = append(items, `"@file:`+part+`"`)
+	}
+	_, err = decodeParamsString(t, `{"A": [`+strings.Join(items, ",")+`]}`)
+	if err == nil || !strings.Contains(err.Error(), "params are larger than") {
+		t.Errorf("params limit err = %v", err)
+	}
+}
+
+func TestConvertContractParams(t *testing.T) {
+	fields := []response.Field{
+		{Name: "Count", Type: "int"},
+		{Name: "Amount", Type: "money"},
+		{Name: "Data", Type: "bytes", Optional: true},
+		{Name: "List", Type: "array", Optional: true},
+	}
+	params, err := decodeParamsString(t, `{"Count": "10", "Amount": 2, "Data": "@bytes:0102", "List": [1, 2.5]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	converted, err := convertContractParams(fields, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if converted["Count"] != int64(10) || converted["Amount"] != "2" {
+		t.Errorf("converted = %#v", converted)
+	}
+	if data, _ := converted["Data"].([]byte); !bytes.Equal(data, []byte{1, 2}) {
+		t.Errorf("Data = %#v", converted["Data"])
+	}
+
+	params, _ = decodeJSONParams(`{"Count": "x", "Data": [1], "Extra": 1}`)
+	_, err = convertContractParams(fields, params)
+	if err == nil {
+		t.Fatal("invalid params accepted")
+	}
+	for _, want := range []string{"param Count (int)", "param Amount (money) is required", "param Data (bytes)", "param Extra isn't a field"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("err = %v, want %q", err, want)
+		}
+	}
+}
+
+func TestConvertFieldValueMoney(t *testing.T) {
+	for _, c := range []struct {
+		value any
+		want  string
+	}{
+		{"1000", "1000"},
+		{float64(25), "25"},
+		{"1e3", "1000"},
+	} {
+		got, err := convertFieldValue("money", c.value)
+		if err != nil || got != c.want {
+			t.Errorf("money %v = %v, %v, want %s", c.value, got, err, c.want)
+		}
+	}
+	for _, value := range []any{"1.5", "-5", "1,5"} {
+		if got, err := convertFieldValue("money", value); err == nil {
+			t.Errorf("money %v = %v, want an error", value, got)
+		}
+	}
+}
+
+func TestDecodeParamTagsAlias(t *testing.T) {
+	path := conf.Config.ConfigPath
+	defer func() { conf.Config.ConfigPath = path }()
+	conf.Config.ConfigPath = filepath.Join(t.TempDir(), "config.yml")
+	book := &conf.AddressBook{Addresses: map[string]conf.AddressEntry{"treasury": {Address: "1234"}}}
+	if err := conf.WriteAddressBook(conf.AddressBookPath(), book); err != nil {
+		t.Fatal(err)
+	}
+	// the strings are kept, only an address field resolves an alias
+	params, err := decodeParamsString(t, `{"Recipient": "@treasury", "Text": "@home", "Escaped": "@@home", "Contract": "@1TokensSend"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"Recipient": "@treasury", "Text": "@home", "Escaped": "@home", "Contract": "@1TokensSend"}
+	for name, value := range want {
+		if params[name] != value {
+			t.Errorf("%s = %#v, want %s", name, params[name], value)
+		}
+	}
+	fields := []response.Field{{Name: "Recipient", Type: "address"}, {Name: "Text", Type: "string"}}
+	converted, err := convertContractParams(fields, map[string]any{"Recipient": "@treasury", "Text": "@home"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if converted["Recipient"] != int64(1234) || converted["Text"] != "@home" {
+		t.Errorf("converted = %#v, want the key id of treasury and @home", converted)
+	}
+	_, err = convertContractParams(fields, map[string]any{"Recipient": "@unknown", "Text": "x"})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("unknown alias of an address field: %v, want not found", err)
+	}
+}
@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/IBAX-io/ibax-cli/conf"
+	"github.com/IBAX-io/ibax-cli/models"
+	"github.com/IBAX-io/ibax-cli/packages/clierr"
+	"github.com/IBAX-io/ibax-cli/packages/parameter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	profileCmd = &cobra.Command{
+		Use:   "profile",
+		Short: "Manage the profiles of config.yml",
+		Long: `
+A profile is a named set of node and key settings in config.yml: rpc_connect, rpc_port, private_key, keystore,
+passphrase_file, ecosystem, cryptoer and hasher. Unset settings inherit the top level settings, which are the "default" profile.
+The active profile is used unless the --profile flag is given, in the console "refresh [profile]" switches it.
+`,
+	}
+
+	profileListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "list the profiles",
+		Long: `
+Request:
+	No parameters required
+
+Returns a json array of profiles, private keys are never printed
+Result:
+	[
+		{
+			"name": "str",				(string) Profile name
+			"active": bool,				(boolean) true for the active profile of config.yml
+			"rpc_connect": "str",		(string) Node address
+			"rpc_port": n,				(number) Node port
+			"ecosystem": n,				(number) Login ecosystem id
+			"key": "str",				(string) private_key | keystore file name | empty
+			"cryptoer": "str",			(string) Key algorithm
+			"hasher": "str"				(string) Hash algorithm
+		}
+	]
+`,
+		Args:       cobra.NoArgs,
+		RunE:       profileList,
+		SuggestFor: []string{"list"},
+		Example:    "./ibax-cli profile list\n./ibax-cli profile list -o table",
+	}
+
+	profileFlags  conf.Profile
+	profileAddCmd = &cobra.Command{
+		Use:   "add [name]",
+		Short: "add a profile",
+		Long: `
+Request:
+	name			(string) Profile name
+
+The settings are given by flags, unset settings inherit the top level settings.
+`,
+		Args:       cobra.ExactArgs(1),
+		RunE:       profileAdd,
+		SuggestFor: []string{"add"},
+		Example:    `./ibax-cli profile add testnet --connect=http://127.0.0.1 --port=7079 --keystore=2023-04-14T02-31-51.000000000Z-UTC-Keystore`,
+	}
+
+	profileRemoveCmd = &cobra.Command{
+		Use:   "remove [name]",
+		Short: "remove a profile",
+		Long: `
+Request:
+	name			(string) Profile name
+
+The default profile is active again if the removed profile was active.
+`,
+		Args:       cobra.ExactArgs(1),
+		RunE:       profileRemove,
+		SuggestFor: []string{"remove"},
+		Example:    `./ibax-cli profile remove testnet`,
+	}
+
+	profileUseCmd = &cobra.Command{
+		Use:   "use [name]",
+		Short: "set the active profile",
+		Long: `
+Request:
+	name			(string) Profile name, "default" for the top level settings
+
+The active profile is saved in config.yml, a running console switches with "refresh [name]".
+`,
+		Args:       cobra.ExactArgs(1),
+		RunE:       profileUse,
+		SuggestFor: []string{"use"},
+		Example:    `./ibax-cli profile use testnet`,
+	}
+)
+
+func init() {
+	profileCmd.AddCommand(
+		profileListCmd,
+		profileAddCmd,
+		profileRemoveCmd,
+		profileUseCmd,
+	)
+	for _, subCommand := range profileCmd.Commands() {
+		for k, v := range subCommand.SuggestFor {
+			subCommand.SuggestFor[k] = profileCmd.Use + " " + v
+			models.AddWordsCompletions(subCommand.SuggestFor)
+		}
+	}
+
+	cmdFlags := profileAddCmd.Flags()
+	cmdFlags.StringVar(&profileFlags.RpcConnect, "connect", "", "Send commands to node running on <connect>")
+	cmdFlags.IntVar(&profileFlags.RpcPort, "port", 0, "Connect to JSON-RPC on <port>")
+	cmdFlags.Int64Var(&profileFlags.Ecosystem, "ecosystem", 0, "login ecosystem id")
+	cmdFlags.StringVar(&profileFlags.PrivateKey, "privateKey", "", "hex private key, or an environment variable like ${OPERATOR_KEY}")
+	cmdFlags.StringVar(&profileFlags.Keystore, "keystore", "", "keystore file name in the keys directory")
+	cmdFlags.StringVar(&profileFlags.PassphraseFile, "passphraseFile", "", "file holding the keystore passphrase")
+	cmdFlags.StringVar(&profileFlags.Cryptoer, "cryptoer", "", "Key and Sign Algorithm")
+	cmdFlags.StringVar(&profileFlags.Hasher, "hasher", "", "Hash Algorithm")
+}
+
+// readProfiles reads config.yml without applying a profile
+func readProfiles() (conf.GlobalConfig, error) {
+	c, err := conf.ReadConfig(conf.Config.ConfigPath)
+	if err != nil {
+		return c, clierr.Wrap(clierr.Config, err, "loading config")
+	}
+	return c, nil
+}
+
+// readProfileDocument reads config.yml as a document, so that the profile edits keep the comments of the file
+func readProfileDocument() (*conf.Document, conf.GlobalConfig, error) {
+	d, err := conf.ReadDocument(conf.Config.ConfigPath)
+	if err != nil {
+		return nil, conf.GlobalConfig{}, clierr.Wrap(clierr.Config, err, "loading config")
+	}
+	c, err := d.Config()
+	if err != nil {
+		return nil, c, clierr.Wrap(clierr.Config, err, "loading config")
+	}
+	return d, c, nil
+}
+
+// writeProfiles saves the document, config.yml holds private keys so it is written 0600
+func writeProfiles(d *conf.Document) error {
+	err := d.Save(conf.Config.ConfigPath)
+	return clierr.Wrap(clierr.Config, err, "Saving config")
+}
+
+func profileList(cmd *cobra.Command, params []string) error {
+	c, err := readProfiles()
+	if err != nil {
+		return err
+	}
+	type profileInfo struct {
+		Name       string `json:"name"`
+		Active     bool   `json:"active"`
+		RpcConnect string `json:"rpc_connect"`
+		RpcPort    int    `json:"rpc_port"`
+		Ecosystem  int64  `json:"ecosystem"`
+		Key        string `json:"key"`
+		Cryptoer   string `json:"cryptoer"`
+		Hasher     string `json:"hasher"`
+	}
+	active := c.Profile
+	if active == "" {
+		active = conf.DefaultProfile
+	}
+	var list []profileInfo
+	for _, name := range c.ProfileNames() {
+		p := c
+		err = p.ApplyProfile(name)
+		if err != nil {
+			return clierr.Wrap(clierr.Config, err, "loading profile")
+		}
+		key := p.Keystore
+		if p.PrivateKey != "" {
+			key = "private_key"
+		}
+		list = append(list, profileInfo{
+			Name:       name,
+			Active:     name == active,
+			RpcConnect: p.RpcConnect,
+			RpcPort:    p.RpcPort,
+			Ecosystem:  p.Ecosystem,
+			Key:        key,
+			Cryptoer:   p.Cryptoer,
+			Hasher:     p.Hasher,
+		})
+	}
+	return printResult(list)
+}
+
+func profileAdd(cmd *cobra.Command, params []string) error {
+	args := parameter.New(params)
+	name, err := args.Set(0, true).String()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "name invalid")
+	}
+	if err := conf.CheckProfileName(name); err != nil {
+		return clierr.Wrap(clierr.Argument, err, "name invalid")
+	}
+	if profileFlags.PrivateKey != "" && profileFlags.Keystore != "" {
+		return clierr.New(clierr.Argument, "privateKey and keystore can't be set both")
+	}
+	d, c, err := readProfileDocument()
+	if err != nil {
+		return err
+	}
+	if c.HasProfile(name) {
+		return clierr.New(clierr.Argument, "profile [%s] already exists", name)
+	}
+	if err = d.SetProfile(name, profileFlags); err != nil {
+		return clierr.Wrap(clierr.Argument, err, "profile invalid")
+	}
+	err = writeProfiles(d)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Profile %s is added to %s\n", name, conf.Config.ConfigPath)
+	return nil
+}
+
+func profileRemove(cmd *cobra.Command, params []string) error {
+	args := parameter.New(params)
+	name, err := args.Set(0, true).String()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "name invalid")
+	}
+	if name == conf.DefaultProfile {
+		return clierr.New(clierr.Argument, "the default profile can't be removed")
+	}
+	d, c, err := readProfileDocument()
+	if err != nil {
+		return err
+	}
+	if !c.HasProfile(name) {
+		return clierr.New(clierr.NotFound, "profile [%s] not found", name)
+	}
+	d.DeleteProfile(name)
+	err = writeProfiles(d)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Profile %s is removed from %s\n", name, conf.Config.ConfigPath)
+	return nil
+}
+
+func profileUse(cmd *cobra.Command, params []string) error {
+	args := parameter.New(params)
+	name, err := args.Set(0, true).String()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "name invalid")
+	}
+	d, c, err := readProfileDocument()
+	if err != nil {
+		return err
+	}
+	if !c.HasProfile(name) {
+		return clierr.New(clierr.NotFound, "profile [%s] not found", name)
+	}
+	if err = d.UseProfile(name); err != nil {
+		return clierr.Wrap(clierr.Config, err, "profile invalid")
+	}
+	err = writeProfiles(d)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Profile %s is active\n", name)
+	return nil
+}
@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"github.com/IBAX-io/go-ibax-sdk/packages/pkg/converter"
 	"github.com/IBAX-io/go-ibax-sdk/packages/request"
+	"github.com/IBAX-io/go-ibax-sdk/packages/response"
 	"github.com/IBAX-io/ibax-cli/conf"
 	"github.com/IBAX-io/ibax-cli/models"
+	"github.com/IBAX-io/ibax-cli/packages/clierr"
+	"github.com/IBAX-io/ibax-cli/packages/money"
 	"github.com/IBAX-io/ibax-cli/packages/parameter"
 	"github.com/gabriel-vasile/mimetype"
 	log "github.com/sirupsen/logrus"
@@ -17,657 +20,513 @@ import (
 	"strconv"
 )
 
-func getKeysInfoCmd(cmd *cobra.Command, args []string) {
+func getKeysInfoCmd(cmd *cobra.Command, args []string) error {
 	if args[0] == "" {
-		log.Infof("Account Address Can't Not Be Empty")
-		return
+		return clierr.New(clierr.Argument, "Account Address Can't Not Be Empty")
+	}
+	account, err := resolveAccount(args[0])
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "account invalid")
 	}
 
-	result, err := models.Client.GetKeyInfo(args[0])
+	result, err := models.Client.GetKeyInfo(account)
 	if err != nil {
-		log.Infof("Get Key Info Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "Get Key Info Failed")
 	}
 	if result == nil {
-		log.Info("Get Key Info Result Empty")
-		return
+		return clierr.New(clierr.NotFound, "Get Key Info Result Empty")
 	}
-	str, err := json.MarshalIndent(*result, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
-	}
-	fmt.Printf("\n%+v\n", string(str))
+	return printResult(*result)
 }
 
-func getBalanceCmd(cmd *cobra.Command, params []string) {
+func getBalanceCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	account, err := args.Set(0, false).String()
 	if err != nil {
-		log.Infof("account invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "account invalid")
+	}
+	account, err = resolveAccount(account)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "account invalid")
 	}
 
 	ecosystemId, err := args.Set(1, false).NumberInt64()
 	if err != nil {
-		log.Infof("ecosystem id invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "ecosystem id invalid")
 	}
 	if account == "" {
 		cnf := models.Client.GetConfig()
 		if cnf.Account != "" {
 			account = cnf.Account
-			fmt.Printf("current account:%s\n", cnf.Account)
+			log.Infof("current account:%s", cnf.Account)
 		} else {
-			fmt.Printf("current account not exist,Please set in the configuration file:%s or specify an account\n", conf.Config.ConfigPath)
-			return
+			return clierr.New(clierr.Config, "current account not exist,Please set in the configuration file:%s or specify an account", conf.Config.ConfigPath)
 		}
 	}
 
 	result, err := models.Client.Balance(account, ecosystemId)
 	if err != nil {
-		log.Infof("Get Balance Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "Get Balance Failed")
 	}
 	if result == nil {
-		log.Info("Get Balance Result Empty")
-		return
-	}
-	str, err := json.MarshalIndent(*result, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
+		return clierr.New(clierr.NotFound, "Get Balance Result Empty")
 	}
-	fmt.Printf("\n%+v\n", string(str))
+	token := money.Token{Symbol: result.TokenSymbol, Digits: int32(result.Digits)}
+	return printResult(balanceResult{
+		TokenBalanceResult: *result,
+		Formatted: balanceFormatted{
+			Amount: token.Format(result.Amount),
+			Utxo:   token.Format(result.Utxo),
+			Total:  token.Format(result.Total),
+		},
+	})
+}
+
+type balanceFormatted struct {
+	Amount string `json:"amount"`
+	Utxo   string `json:"utxo"`
+	Total  string `json:"total"`
+}
+
+// balanceResult is the balance of getBalance with the amounts in tokens
+type balanceResult struct {
+	response.TokenBalanceResult
+	Formatted balanceFormatted `json:"formatted"`
 }
 
-func getVersionCmd(cmd *cobra.Command, args []string) {
+func getVersionCmd(cmd *cobra.Command, args []string) error {
 	err := cobra.NoArgs(cmd, args)
 	if err != nil {
-		log.Infof("no parameters required: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "no parameters required")
 	}
 
 	result, err := models.Client.GetVersion()
 	if err != nil {
-		log.Infof("Get Version Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "Get Version Failed")
 	}
 	if result == nil {
-		log.Info("Get Version Result Empty")
-		return
+		return clierr.New(clierr.NotFound, "Get Version Result Empty")
 	}
-	fmt.Printf("%s\n", *result)
+	return printResult(*result)
 }
 
-func getChainConfigCmd(cmd *cobra.Command, args []string) {
+func getChainConfigCmd(cmd *cobra.Command, args []string) error {
 	if args[0] == "" {
-		log.Infof("Option Can't Not Be Empty")
-		return
+		return clierr.New(clierr.Argument, "Option Can't Not Be Empty")
 	}
 
 	result, err := models.Client.GetIBAXConfig(args[0])
 	if err != nil {
-		log.Infof("Get Chain Config Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "Get Chain Config Failed")
 	}
 	if result == nil {
-		log.Info("Get Chain Config Result Empty")
-		return
+		return clierr.New(clierr.NotFound, "Get Chain Config Result Empty")
 	}
-	fmt.Printf("%s\n", *result)
+	return printResult(*result)
 }
 
-func getEcosystemCountCmd(cmd *cobra.Command, args []string) {
+func getEcosystemCountCmd(cmd *cobra.Command, args []string) error {
 	err := cobra.NoArgs(cmd, args)
 	if err != nil {
-		log.Infof("no parameters required: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "no parameters required")
 	}
 
 	count, err := models.Client.EcosystemCount()
 	if err != nil {
-		log.Infof("Get Version Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "Get Version Failed")
 	}
 
-	fmt.Printf("\n%d\n", count)
+	return printResult(count)
 }
 
-func getMaxBlockCmd(cmd *cobra.Command, args []string) {
+func getMaxBlockCmd(cmd *cobra.Command, args []string) error {
 	err := cobra.NoArgs(cmd, args)
 	if err != nil {
-		log.Infof("no parameters required: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "no parameters required")
 	}
 
 	count, err := models.Client.GetMaxBlockID()
 	if err != nil {
-		log.Infof("Get Max Block Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "Get Max Block Failed")
 	}
 
-	fmt.Printf("\n%d\n", count)
+	return printResult(count)
 }
 
-func getTxCountCmd(cmd *cobra.Command, args []string) {
+func getTxCountCmd(cmd *cobra.Command, args []string) error {
 	err := cobra.NoArgs(cmd, args)
 	if err != nil {
-		log.Infof("no parameters required: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "no parameters required")
 	}
 
 	count, err := models.Client.TransactionsCount()
 	if err != nil {
-		log.Infof("Get Transaction Count Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "Get Transaction Count Failed")
 	}
 
-	fmt.Printf("\n%d\n", count)
+	return printResult(count)
 }
 
-func getKeysCountCmd(cmd *cobra.Command, args []string) {
+func getKeysCountCmd(cmd *cobra.Command, args []string) error {
 	err := cobra.NoArgs(cmd, args)
 	if err != nil {
-		log.Infof("no parameters required: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "no parameters required")
 	}
 
 	count, err := models.Client.KeysCount()
 	if err != nil {
-		log.Infof("Get keys Count Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "Get keys Count Failed")
 	}
 
-	fmt.Printf("\n%d\n", count)
+	return printResult(count)
 }
 
-func getHonorNodesCountCmd(cmd *cobra.Command, args []string) {
+func getHonorNodesCountCmd(cmd *cobra.Command, args []string) error {
 	err := cobra.NoArgs(cmd, args)
 	if err != nil {
-		log.Infof("no parameters required: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "no parameters required")
 	}
 
 	count, err := models.Client.HonorNodesCount()
 	if err != nil {
-		log.Infof("Get Honor Nodes Count Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "Get Honor Nodes Count Failed")
 	}
 
-	fmt.Printf("\n%d\n", count)
+	return printResult(count)
 }
 
-func detailBlocksCmd(cmd *cobra.Command, params []string) {
+func detailBlocksCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	blockId, err := args.Set(0, true).NumberInt64()
 	if err != nil {
-		log.Infof("BlockId invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "BlockId invalid")
 	}
 
 	count, err := args.Set(1, false).NumberInt64()
 	if err != nil {
-		log.Infof("Count invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "Count invalid")
 	}
 
-	result, err := models.Client.DetailedBlocks(blockId, count)
+	result, err := cachedBlocks(blockId, count)
 	if err != nil {
-		log.Infof("Detailed Blocks Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "Detailed Blocks Failed")
 	}
 	if result == nil {
-		log.Info("Detailed Blocks Result Empty")
-		return
-	}
-	str, err := json.MarshalIndent(*result, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
+		return clierr.New(clierr.NotFound, "Detailed Blocks Result Empty")
 	}
-	fmt.Printf("\n%+v\n", string(str))
+	return printResult(*result)
 }
 
-func getBlockInfoCmd(cmd *cobra.Command, params []string) {
+func getBlockInfoCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	blockId, err := args.Set(0, true).NumberInt64()
 	if err != nil {
-		log.Infof("blockId invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "blockId invalid")
 	}
 
-	result, err := models.Client.GetBlockInfo(blockId)
+	result, err := cachedBlockInfo(blockId)
 	if err != nil {
-		log.Infof("get Block Info Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "get Block Info Failed")
 	}
 	if result == nil {
-		log.Info("Block Info Result Empty")
-		return
+		return clierr.New(clierr.NotFound, "Block Info Result Empty")
 	}
-	str, err := json.MarshalIndent(*result, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
-	}
-	fmt.Printf("\n%+v\n", string(str))
+	return printResult(*result)
 }
 
-func getBlocksTxInfoCmd(cmd *cobra.Command, params []string) {
+func getBlocksTxInfoCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	blockId, err := args.Set(0, true).NumberInt64()
 	if err != nil {
-		log.Infof("blockId invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "blockId invalid")
 	}
 	count, err := args.Set(1, false).NumberInt64()
 	if err != nil {
-		log.Infof("count invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "count invalid")
 	}
 
-	result, err := models.Client.BlocksTxInfo(blockId, count)
+	result, err := cachedBlocksTxInfo(blockId, count)
 	if err != nil {
-		log.Infof("get blocks tx info Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "get blocks tx info Failed")
 	}
 	if result == nil {
-		log.Info("get blocks tx info Result Empty")
-		return
-	}
-	str, err := json.MarshalIndent(*result, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
+		return clierr.New(clierr.NotFound, "get blocks tx info Result Empty")
 	}
-	fmt.Printf("\n%+v\n", string(str))
+	return printResult(*result)
 }
 
-func getTableCountCmd(cmd *cobra.Command, params []string) {
+func getTableCountCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	offset, err := args.Set(0, false).NumberInt()
 	if err != nil {
-		log.Infof("offset invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "offset invalid")
 	}
 	limit, err := args.Set(1, false).NumberInt()
 	if err != nil {
-		log.Infof("limit invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "limit invalid")
 	}
 
 	result, err := models.Client.GetTableCount(offset, limit)
 	if err != nil {
-		log.Infof("get table count Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "get table count Failed")
 	}
 	if result == nil {
-		log.Info("get table count Result Empty")
-		return
+		return clierr.New(clierr.NotFound, "get table count Result Empty")
 	}
-	str, err := json.MarshalIndent(*result, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
-	}
-	fmt.Printf("\n%+v\n", string(str))
+	return printResult(*result)
 }
 
-func getTableCmd(cmd *cobra.Command, params []string) {
+func getTableCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	tableName, err := args.Set(0, true).String()
 	if err != nil {
-		log.Infof("offset invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "offset invalid")
 	}
 
 	result, err := models.Client.GetTable(tableName)
 	if err != nil {
-		log.Infof("get table Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "get table Failed")
 	}
 	if result == nil {
-		log.Info("get table Result Empty")
-		return
+		return clierr.New(clierr.NotFound, "get table Result Empty")
 	}
-	str, err := json.MarshalIndent(*result, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
-	}
-	fmt.Printf("\n%+v\n", string(str))
+	return printResult(*result)
 }
 
-func getSectionsCmd(cmd *cobra.Command, params []string) {
+func getSectionsCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	language, err := args.Set(0, false).String()
 	if err != nil {
-		log.Infof("language invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "language invalid")
 	}
 	offset, err := args.Set(1, false).NumberInt()
 	if err != nil {
-		log.Infof("offset invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "offset invalid")
 	}
 	limit, err := args.Set(2, false).NumberInt()
 	if err != nil {
-		log.Infof("limit invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "limit invalid")
 	}
 
 	result, err := models.Client.GetSections(language, offset, limit)
 	if err != nil {
-		log.Infof("get sections Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "get sections Failed")
 	}
 	if result == nil {
-		log.Info("get sections Result Empty")
-		return
+		return clierr.New(clierr.NotFound, "get sections Result Empty")
 	}
-	str, err := json.MarshalIndent(*result, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
-	}
-	fmt.Printf("\n%+v\n", string(str))
+	return printResult(*result)
 }
 
-func getPageRowCmd(cmd *cobra.Command, params []string) {
+func getPageRowCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	name, err := args.Set(0, true).String()
 	if err != nil {
-		log.Infof("name invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "name invalid")
 	}
 
 	result, err := models.Client.GetPageRow(name)
 	if err != nil {
-		log.Infof("get page row Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "get page row Failed")
 	}
 	if result == nil {
-		log.Info("get page row Result Empty")
-		return
-	}
-	str, err := json.MarshalIndent(*result, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
+		return clierr.New(clierr.NotFound, "get page row Result Empty")
 	}
-	fmt.Printf("\n%+v\n", string(str))
+	return printResult(*result)
 }
 
-func getMenuRowCmd(cmd *cobra.Command, params []string) {
+func getMenuRowCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	name, err := args.Set(0, true).String()
 	if err != nil {
-		log.Infof("name invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "name invalid")
 	}
 
 	result, err := models.Client.GetMenuRow(name)
 	if err != nil {
-		log.Infof("get menu row Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "get menu row Failed")
 	}
 	if result == nil {
-		log.Info("get menu row Result Empty")
-		return
+		return clierr.New(clierr.NotFound, "get menu row Result Empty")
 	}
-	str, err := json.MarshalIndent(*result, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
-	}
-	fmt.Printf("\n%+v\n", string(str))
+	return printResult(*result)
 }
 
-func getSnippetRowCmd(cmd *cobra.Command, params []string) {
+func getSnippetRowCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	name, err := args.Set(0, true).String()
 	if err != nil {
-		log.Infof("name invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "name invalid")
 	}
 
 	result, err := models.Client.GetSnippetRow(name)
 	if err != nil {
-		log.Infof("get snippet row Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "get snippet row Failed")
 	}
 	if result == nil {
-		log.Info("get snippet row Result Empty")
-		return
-	}
-	str, err := json.MarshalIndent(*result, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
+		return clierr.New(clierr.NotFound, "get snippet row Result Empty")
 	}
-	fmt.Printf("\n%+v\n", string(str))
+	return printResult(*result)
 }
 
-func getAppContentCmd(cmd *cobra.Command, params []string) {
+func getAppContentCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	appId, err := args.Set(0, true).NumberInt64()
 	if err != nil {
-		log.Infof("appId invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "appId invalid")
 	}
 
 	result, err := models.Client.GetAppContent(appId)
 	if err != nil {
-		log.Infof("get app content Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "get app content Failed")
 	}
 	if result == nil {
-		log.Info("get app content Result Empty")
-		return
-	}
-	str, err := json.MarshalIndent(*result, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
+		return clierr.New(clierr.NotFound, "get app content Result Empty")
 	}
-	fmt.Printf("\n%+v\n", string(str))
+	return printResult(*result)
 }
 
-func appParamsCmd(cmd *cobra.Command, params []string) {
+func appParamsCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	appId, err := args.Set(0, true).NumberInt64()
 	if err != nil {
-		log.Infof("appId invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "appId invalid")
 	}
 	names, err := args.Set(1, false).String()
 	if err != nil {
-		log.Infof("names invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "names invalid")
 	}
 	ecosystemId, err := args.Set(2, false).NumberInt64()
 	if err != nil {
-		log.Infof("ecosystemId invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "ecosystemId invalid")
 	}
 	offset, err := args.Set(3, false).NumberInt()
 	if err != nil {
-		log.Infof("offset invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "offset invalid")
 	}
 	limit, err := args.Set(4, false).NumberInt()
 	if err != nil {
-		log.Infof("limit invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "limit invalid")
 	}
 
 	result, err := models.Client.AppParams(appId, names, ecosystemId, offset, limit)
 	if err != nil {
-		log.Infof("get app params Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "get app params Failed")
 	}
 	if result == nil {
-		log.Info("get app params Result Empty")
-		return
-	}
-	str, err := json.MarshalIndent(*result, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
+		return clierr.New(clierr.NotFound, "get app params Result Empty")
 	}
-	fmt.Printf("\n%+v\n", string(str))
+	return printResult(*result)
 }
 
-func ecosystemParamsCmd(cmd *cobra.Command, params []string) {
+func ecosystemParamsCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	ecosystemId, err := args.Set(0, false).NumberInt64()
 	if err != nil {
-		log.Infof("ecosystemId invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "ecosystemId invalid")
 	}
 	names, err := args.Set(1, false).String()
 	if err != nil {
-		log.Infof("names invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "names invalid")
 	}
 	offset, err := args.Set(2, false).NumberInt()
 	if err != nil {
-		log.Infof("offset invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "offset invalid")
 	}
 	limit, err := args.Set(3, false).NumberInt()
 	if err != nil {
-		log.Infof("limit invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "limit invalid")
 	}
 
 	result, err := models.Client.EcosystemParams(ecosystemId, names, offset, limit)
 	if err != nil {
-		log.Infof("get ecosystem params Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "get ecosystem params Failed")
 	}
 	if result == nil {
-		log.Info("get ecosystem params Result Empty")
-		return
+		return clierr.New(clierr.NotFound, "get ecosystem params Result Empty")
 	}
-	str, err := json.MarshalIndent(*result, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
-	}
-	fmt.Printf("\n%+v\n", string(str))
+	return printResult(*result)
 }
 
-func systemParamsCmd(cmd *cobra.Command, params []string) {
+func systemParamsCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	names, err := args.Set(0, false).String()
 	if err != nil {
-		log.Infof("names invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "names invalid")
 	}
 	offset, err := args.Set(1, false).NumberInt()
 	if err != nil {
-		log.Infof("offset invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "offset invalid")
 	}
 	limit, err := args.Set(2, false).NumberInt()
 	if err != nil {
-		log.Infof("limit invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "limit invalid")
 	}
 
 	result, err := models.Client.SystemParams(names, offset, limit)
 	if err != nil {
-		log.Infof("get system params Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "get system params Failed")
 	}
 	if result == nil {
-		log.Info("get system params Result Empty")
-		return
-	}
-	str, err := json.MarshalIndent(*result, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
+		return clierr.New(clierr.NotFound, "get system params Result Empty")
 	}
-	fmt.Printf("\n%+v\n", string(str))
+	return printResult(*result)
 }
 
-func getRowCmd(cmd *cobra.Command, params []string) {
+func getRowCmd(cmd *cobra.Command, params []string) error {
 	rowParams.lock.Lock()
 	defer rowParams.lock.Unlock()
 	args := parameter.New(params)
 	tableName, err := args.Set(0, true).String()
 	if err != nil {
-		log.Infof("tableName invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "tableName invalid")
 	}
 	columns, err := args.Set(1, false).String()
 	if err != nil {
-		log.Infof("columns invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "columns invalid")
 	}
 	WhereColumn, err := args.Set(2, false).String()
 	if err != nil {
-		log.Infof("WhereColumn invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "WhereColumn invalid")
 	}
 
 	result, err := models.Client.GetRow(tableName, rowParams.Id, columns, WhereColumn)
 	if err != nil {
-		log.Infof("get row Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "get row Failed")
 	}
 	if result == nil {
-		log.Info("get row Result Empty")
-		return
-	}
-	str, err := json.MarshalIndent(*result, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
+		return clierr.New(clierr.NotFound, "get row Result Empty")
 	}
-	fmt.Printf("\n%+v\n", string(str))
+	return printResult(*result)
 }
 
-func getHistoryCmd(cmd *cobra.Command, params []string) {
+func getHistoryCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	tableName, err := args.Set(0, true).String()
 	if err != nil {
-		log.Infof("tableName invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "tableName invalid")
 	}
 
 	id, err := args.Set(1, true).NumberUint64()
 	if err != nil {
-		log.Infof("tableName invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "tableName invalid")
 	}
 
 	result, err := models.Client.GetHistory(tableName, id)
 	if err != nil {
-		log.Infof("get history Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "get history Failed")
 	}
 	if result == nil {
-		log.Info("get history Result Empty")
-		return
+		return clierr.New(clierr.NotFound, "get history Result Empty")
 	}
-	str, err := json.MarshalIndent(*result, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
-	}
-	fmt.Printf("\n%+v\n", string(str))
+	return printResult(*result)
 }
 
-func getListCmd(cmd *cobra.Command, params []string) {
+func getListCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	tableName, err := args.Set(0, true).String()
 	if err != nil {
-		log.Infof("tableName invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "tableName invalid")
 	}
 
 	getListParams.Name = tableName
@@ -677,8 +536,7 @@ func getListCmd(cmd *cobra.Command, params []string) {
 	if getListParams.orderStr != "" {
 		err = json.Unmarshal([]byte(getListParams.orderStr), &getListParams.Order)
 		if err != nil {
-			log.Infof("order invalid:%s", err.Error())
-			return
+			return clierr.Wrap(clierr.Argument, err, "order invalid")
 		}
 	}
 	defer func() {
@@ -688,22 +546,15 @@ func getListCmd(cmd *cobra.Command, params []string) {
 
 	result, err := models.Client.GetList(getListParams.GetList)
 	if err != nil {
-		log.Infof("get list Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "get list Failed")
 	}
 	if result == nil {
-		log.Info("get list Result Empty")
-		return
-	}
-	str, err := json.MarshalIndent(*result, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
+		return clierr.New(clierr.NotFound, "get list Result Empty")
 	}
-	fmt.Printf("\n%+v\n", string(str))
+	return printResult(*result)
 }
 
-func blockTxCountCmd(cmd *cobra.Command, params []string) {
+func blockTxCountCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	var bh request.BlockIdOrHash
 	var err error
@@ -711,19 +562,17 @@ func blockTxCountCmd(cmd *cobra.Command, params []string) {
 	if err != nil {
 		bh.Hash, err = args.Set(0, true).String()
 		if err != nil {
-			log.Infof("block id or block hash invalid:%s", err.Error())
-			return
+			return clierr.Wrap(clierr.Argument, err, "block id or block hash invalid")
 		}
 	}
 	count, err := models.Client.BlockTxCount(bh)
 	if err != nil {
-		log.Infof("block tx count Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "block tx count Failed")
 	}
-	fmt.Printf("\n%d\n", count)
+	return printResult(count)
 }
 
-func detailedBlockCmd(cmd *cobra.Command, params []string) {
+func detailedBlockCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	var bh request.BlockIdOrHash
 	var err error
@@ -731,121 +580,90 @@ func detailedBlockCmd(cmd *cobra.Command, params []string) {
 	if err != nil {
 		bh.Hash, err = args.Set(0, true).String()
 		if err != nil {
-			log.Infof("block id or block hash invalid:%s", err.Error())
-			return
+			return clierr.Wrap(clierr.Argument, err, "block id or block hash invalid")
 		}
 	}
 
-	result, err := models.Client.DetailedBlock(bh)
+	result, err := cachedBlock(bh)
 	if err != nil {
-		log.Infof("detailed Block Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "detailed Block Failed")
 	}
 	if result == nil {
-		log.Info("detailed Block Result Empty")
-		return
+		return clierr.New(clierr.NotFound, "detailed Block Result Empty")
 	}
-	str, err := json.MarshalIndent(*result, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
-	}
-	fmt.Printf("\n%+v\n", string(str))
+	return printResult(*result)
 }
 
-func ecosystemInfoCmd(cmd *cobra.Command, params []string) {
+func ecosystemInfoCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	ecosystemId, err := args.Set(0, true).NumberInt64()
 	if err != nil {
-		log.Infof("ecosystem Id invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "ecosystem Id invalid")
 	}
 
 	result, err := models.Client.EcosystemInfo(ecosystemId)
 	if err != nil {
-		log.Infof("Ecosystem Info Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "Ecosystem Info Failed")
 	}
 	if result == nil {
-		log.Info("Ecosystem Info Result Empty")
-		return
-	}
-	str, err := json.MarshalIndent(*result, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
+		return clierr.New(clierr.NotFound, "Ecosystem Info Result Empty")
 	}
-	fmt.Printf("\n%+v\n", string(str))
+	return printResult(*result)
 }
 
-func getMemberInfoCmd(cmd *cobra.Command, params []string) {
+func getMemberInfoCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	account, err := args.Set(0, true).String()
 	if err != nil {
-		log.Infof("account invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "account invalid")
+	}
+	account, err = resolveAccount(account)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "account invalid")
 	}
 	keyId := converter.StringToAddress(account)
 	if keyId == 0 {
-		log.Infof("account[%s] invalid:%s", account, "format not supported")
-		return
+		return clierr.New(clierr.Argument, "account[%s] invalid:%s", account, "format not supported")
 	}
 
 	ecosystemId, err := args.Set(1, true).NumberInt64()
 	if err != nil {
-		log.Infof("ecosystem Id invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "ecosystem Id invalid")
 	}
 
 	result, err := models.Client.GetMemberInfo(account, ecosystemId)
 	if err != nil {
-		log.Infof("Get Member Info Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "Get Member Info Failed")
 	}
 	if result == nil {
-		log.Info("Get Member Info Result Empty")
-		return
-	}
-	str, err := json.MarshalIndent(*result, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
+		return clierr.New(clierr.NotFound, "Get Member Info Result Empty")
 	}
-	fmt.Printf("\n%+v\n", string(str))
+	return printResult(*result)
 }
 
-func binaryVerifyCmd(cmd *cobra.Command, params []string) {
+func binaryVerifyCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	binaryId, err := args.Set(0, true).NumberInt64()
 	if err != nil {
-		log.Infof("binary id invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "binary id invalid")
 	}
 	binaryHash, err := args.Set(1, true).String()
 	if err != nil {
-		log.Infof("binary hash invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "binary hash invalid")
 	}
 
 	fileInfo, err := models.Client.BinaryVerify(binaryId, binaryHash, binaryFileName)
 	if err != nil {
-		fmt.Printf("binary verify failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "binary verify failed")
 	}
-	str, err := json.MarshalIndent(fileInfo, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
-	}
-	fmt.Printf("\n%+v\n", string(str))
+	return printResult(fileInfo)
 }
 
-func exportCmd(cmd *cobra.Command, params []string) {
+func exportCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	appId, err := args.Set(0, true).NumberInt64()
 	if err != nil {
-		log.Infof("account invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "account invalid")
 	}
 	cnf := models.Client.GetConfig()
 
@@ -855,40 +673,26 @@ func exportCmd(cmd *cobra.Command, params []string) {
 	contractParams["ApplicationId"] = appId
 	result, err := models.Client.AutoCallContract("@1ExportNewApp", &contractParams, "")
 	if err != nil {
-		log.Infof("call @1ExportNewApp Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "call @1ExportNewApp Failed")
 	}
 	if result == nil {
-		log.Info("call @1ExportNewApp Result Empty")
-		return
+		return clierr.New(clierr.NotFound, "call @1ExportNewApp Result Empty")
 	}
 	if result.BlockId == 0 || result.Hash == "" || result.Penalty == 1 || result.Err != "" {
-		str, err := json.MarshalIndent(*result, "", "    ")
-		if err != nil {
-			fmt.Printf("call @1ExportNewApp Result marshall Failed:%s\n", err.Error())
-			return
-		}
-		fmt.Printf("export process @1ExportNewApp failed: \n%+v\n", string(str))
-		return
+		str, _ := json.Marshal(*result)
+		return clierr.New(clierr.Rejected, "export process @1ExportNewApp failed: %s", string(str))
 	}
 
 	result, err = models.Client.AutoCallContract("@1Export", nil, "")
 	if err != nil {
-		log.Infof("call @1Export Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "call @1Export Failed")
 	}
 	if result == nil {
-		log.Info("call @1Export Result Empty")
-		return
+		return clierr.New(clierr.NotFound, "call @1Export Result Empty")
 	}
 	if result.BlockId == 0 || result.Hash == "" || result.Penalty == 1 || result.Err != "" {
-		str, err := json.MarshalIndent(*result, "", "    ")
-		if err != nil {
-			fmt.Printf("call @1Export Result marshall Failed:%s\n", err.Error())
-			return
-		}
-		fmt.Printf("export process @1Export failed: \n%+v\n", string(str))
-		return
+		str, _ := json.Marshal(*result)
+		return clierr.New(clierr.Rejected, "export process @1Export failed: %s", string(str))
 	}
 	var getListParams request.GetList
 	getListParams.Name = "@1binaries"
@@ -897,12 +701,10 @@ func exportCmd(cmd *cobra.Command, params []string) {
 	getListParams.Where = fmt.Sprintf(`{"name": "export", "account": "%s", "ecosystem": %d, "app_id": %d}`, account, ecosystem, appId)
 	listResult, err := models.Client.GetList(getListParams)
 	if err != nil {
-		fmt.Printf("export process GetList failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "export process GetList failed")
 	}
 	if listResult == nil {
-		log.Info("export process GetList Result Empty")
-		return
+		return clierr.New(clierr.NotFound, "export process GetList Result Empty")
 	}
 
 	var binaryId int64
@@ -919,48 +721,33 @@ func exportCmd(cmd *cobra.Command, params []string) {
 		}
 	}
 	if binaryHash == "" || binaryId == 0 {
-		str, err := json.MarshalIndent(*listResult, "", "    ")
-		if err != nil {
-			fmt.Printf("GetList Result marshall Failed:%s\n", err.Error())
-			return
-		}
-		fmt.Printf("export process GetList failed binaryHash or binaryId empty: \n%+v\n", string(str))
-		return
+		str, _ := json.Marshal(*listResult)
+		return clierr.New(clierr.NotFound, "export process GetList failed binaryHash or binaryId empty: %s", string(str))
 	}
 
 	fileInfo, err := models.Client.BinaryVerify(binaryId, binaryHash, exportFileName)
 	if err != nil {
-		fmt.Printf("export process GetBinary failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "export process GetBinary failed")
 	}
-	str, err := json.MarshalIndent(fileInfo, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
-	}
-	fmt.Printf("\n%+v\n", string(str))
+	return printResult(fileInfo)
 }
 
-func importCmd(cmd *cobra.Command, params []string) {
+func importCmd(cmd *cobra.Command, params []string) error {
 	err := cobra.NoArgs(cmd, params)
 	if err != nil {
-		log.Infof("no parameters required: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "no parameters required")
 	}
 	if importFileName == "" {
-		log.Info("import file name can't not be empty")
-		return
+		return clierr.New(clierr.Argument, "import file name can't not be empty")
 	}
 
 	data, err := os.ReadFile(importFileName)
 	if err != nil {
-		log.Infof("filename: [%s] readfile err: %s", importFileName, err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, fmt.Sprintf("filename: [%s] readfile err", importFileName))
 	}
 	mimeType, _, err := getMimeType(importFileName)
 	if err != nil {
-		log.Infof("filename: [%s] getMimeType err: %s", importFileName, err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, fmt.Sprintf("filename: [%s] getMimeType err", importFileName))
 	}
 
 	importInfo := make(map[string]any)
@@ -971,21 +758,14 @@ func importCmd(cmd *cobra.Command, params []string) {
 	contractParams["Data"] = importInfo
 	result, err := models.Client.AutoCallContract("@1ImportUpload", &contractParams, "")
 	if err != nil {
-		log.Infof("call @1ImportUpload Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "call @1ImportUpload Failed")
 	}
 	if result == nil {
-		log.Info("call @1ImportUpload Result Empty")
-		return
+		return clierr.New(clierr.NotFound, "call @1ImportUpload Result Empty")
 	}
 	if result.BlockId == 0 || result.Hash == "" || result.Penalty == 1 || result.Err != "" {
-		str, err := json.MarshalIndent(*result, "", "    ")
-		if err != nil {
-			fmt.Printf("call @1ImportUpload Result marshall Failed:%s\n", err.Error())
-			return
-		}
-		fmt.Printf("import process @1ImportUpload failed: \n%+v\n", string(str))
-		return
+		str, _ := json.Marshal(*result)
+		return clierr.New(clierr.Rejected, "import process @1ImportUpload failed: %s", string(str))
 	}
 
 	cnf := models.Client.GetConfig()
@@ -996,12 +776,10 @@ func importCmd(cmd *cobra.Command, params []string) {
 	getListParams.Where = fmt.Sprintf(`{"key": "import", "account": "%s", "ecosystem": %d}`, cnf.Account, cnf.Ecosystem)
 	listResult, err := models.Client.GetList(getListParams)
 	if err != nil {
-		fmt.Printf("import process GetList failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "import process GetList failed")
 	}
 	if listResult == nil {
-		log.Info("import process GetList Result Empty")
-		return
+		return clierr.New(clierr.NotFound, "import process GetList Result Empty")
 	}
 	var bufferData string
 	var ret = make([]any, 0)
@@ -1011,7 +789,7 @@ func importCmd(cmd *cobra.Command, params []string) {
 			var d []map[string]any
 			err := json.Unmarshal([]byte(value), &d)
 			if err != nil {
-				return
+				return clierr.Wrap(clierr.Unknown, err, "import process buffer data parsing failed")
 			}
 			for _, i2 := range d {
 				a, ok := i2["Data"]
@@ -1019,7 +797,7 @@ func importCmd(cmd *cobra.Command, params []string) {
 					var b []any
 					err := json.Unmarshal([]byte(a.(string)), &b)
 					if err != nil {
-						return
+						return clierr.Wrap(clierr.Unknown, err, "import process buffer data parsing failed")
 					}
 					ret = append(ret, b...)
 				}
@@ -1029,93 +807,74 @@ func importCmd(cmd *cobra.Command, params []string) {
 		}
 	}
 	if bufferData == "" {
-		str, err := json.MarshalIndent(*listResult, "", "    ")
-		if err != nil {
-			fmt.Printf("GetList Result marshall Failed:%s\n", err.Error())
-			return
-		}
-		fmt.Printf("import process GetList failed bufferData empty: \n%+v\n", string(str))
-		return
+		str, _ := json.Marshal(*listResult)
+		return clierr.New(clierr.NotFound, "import process GetList failed bufferData empty: %s", string(str))
 	}
 
 	contractParams["Data"] = bufferData
 	importResult, err := models.Client.AutoCallContract("@1Import", &contractParams, "")
 	if err != nil {
-		log.Infof("call @1Import Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "call @1Import Failed")
 	}
 	if importResult == nil {
-		log.Info("call @1Import Result Empty")
-		return
+		return clierr.New(clierr.NotFound, "call @1Import Result Empty")
 	}
 
-	str, err := json.MarshalIndent(importResult, "", "    ")
-	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
-	}
-	fmt.Printf("\n%+v\n", string(str))
+	return printResult(importResult)
 }
 
 func getMimeType(fileName string) (string, string, error) {
 	mType, err := mimetype.DetectFile(fileName)
 	if err != nil {
-		fmt.Printf("DetectFile err:%s\n", err.Error())
+		log.Infof("DetectFile err:%s", err.Error())
 		return "", "", err
 	}
 	return mType.String(), mType.Extension(), nil
 }
 
-func base64DecodeCmd(cmd *cobra.Command, params []string) {
+func base64DecodeCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	decodeData, err := args.Set(0, false).String()
 	if err != nil {
-		log.Infof("data invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "data invalid")
 	}
 	if decodeData == "" && decodeFileName == "" {
-		log.Info("decode data and file name must have one")
-		return
+		return clierr.New(clierr.Argument, "decode data and file name must have one")
 	}
 
 	data, err := base64.StdEncoding.DecodeString(decodeData)
 	if err != nil {
-		log.Infof("data Decode failed:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "data Decode failed")
 	}
 
 	if decodeFileName != "" {
 		err := os.WriteFile(decodeFileName, data, 0644)
 		if err != nil {
-			log.Info(err)
-			return
+			return clierr.Wrap(clierr.Unknown, err, "write decode file failed")
 		}
-	} else {
-		fmt.Printf("\nDecode:%s\n", string(data))
+		return nil
 	}
+	return printResult(string(data))
 }
 
-func base64EncodeCmd(cmd *cobra.Command, params []string) {
+func base64EncodeCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	encodeData, err := args.Set(0, false).String()
 	if err != nil {
-		log.Infof("data invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "data invalid")
 	}
 	if encodeData == "" && encodeFileName == "" {
-		log.Info("encode data and file name must have one")
-		return
+		return clierr.New(clierr.Argument, "encode data and file name must have one")
 	}
 
 	var data []byte
 	if encodeFileName != "" {
 		data, err = os.ReadFile(encodeFileName)
 		if err != nil {
-			log.Infof("ReadFile failed: %s", err.Error())
-			return
+			return clierr.Wrap(clierr.Argument, err, "ReadFile failed")
 		}
 	} else {
 		data = []byte(encodeData)
 	}
-	fmt.Printf("\nEncode:%s\n", base64.StdEncoding.EncodeToString(data))
+	return printResult(base64.StdEncoding.EncodeToString(data))
 }
@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/IBAX-io/ibax-cli/conf"
+	"github.com/IBAX-io/ibax-cli/models"
+	"github.com/IBAX-io/ibax-cli/packages/clierr"
+	"github.com/IBAX-io/ibax-cli/packages/txfile"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	txAsync             bool
+	txConfirmations     int64
+	txWaitConfirmations int64
+)
+
+const (
+	TxPending   = "pending"
+	TxRejected  = "rejected"
+	TxCommitted = "committed"
+	TxPenalized = "penalized"
+)
+
+const txReceiptDoc = `
+Returns a json object transaction receipt.
+Result:
+	{
+		"hash": "str",				(string) Transaction hash
+		"status": "str",			(string) pending (not in a block yet) || rejected (not accepted in a block) || committed || penalized
+		"block_id": n,				(number) The block id of the transaction, 0 if it isn't in a block
+		"penalty": n,				(number) If transaction execution fails, (0: no penalty 1: penalty)
+		"err": "str",				(string, optional) The error text of a rejected or penalized transaction
+		"result": "str",			(string, optional) The result of the contract
+		"max_block_id": n,			(number) The latest block id of the node
+		"confirmations": n			(number) max_block_id - block_id + 1, 0 if it isn't in a block
+	}
+`
+
+const asyncDoc = `
+With --async the transaction is signed and sent, and its hash is returned right away:
+	{
+		"hash": "str"				(string) Transaction hash, see "tx status" and "tx wait"
+	}
+With --confirmations n the call waits until n blocks, the block of the transaction included, are on top of it,
+and returns the transaction receipt of "tx status", also for 1. Without it the result of the node is returned.
+`
+
+type txReceipt struct {
+	Hash          string `json:"hash"`
+	Status        string `json:"status"`
+	BlockId       int64  `json:"block_id"`
+	Penalty       int64  `json:"penalty"`
+	Err           string `json:"err,omitempty"`
+	Result        string `json:"result,omitempty"`
+	MaxBlockId    int64  `json:"max_block_id"`
+	Confirmations int64  `json:"confirmations"`
+}
+
+// final reports whether the status of the transaction won't change
+func (r *txReceipt) final() bool {
+	return r.Status != TxPending
+}
+
+// failed reports whether the transaction is rejected or penalized
+func (r *txReceipt) failed() bool {
+	return r.Status == TxRejected || r.Status == TxPenalized
+}
+
+// getTxReceipt returns the status of the transaction and its depth below the latest block
+func getTxReceipt(hash string) (*txReceipt, error) {
+	type txStatusError struct {
+		Type  string `json:"type"`
+		Error string `json:"error"`
+		Id    string `json:"id"`
+	}
+	type txStatus struct {
+		BlockID string         `json:"blockid"`
+		Message *txStatusError `json:"errmsg"`
+		Result  string         `json:"result"`
+		Penalty int64          `json:"penalty"`
+	}
+	var statuses map[string]txStatus
+	err := models.CallRPC("ibax.txStatus", &statuses, hash)
+	if err != nil {
+		return nil, err
+	}
+	status, ok := statuses[hash]
+	if !ok {
+		return nil, fmt.Errorf("hash %s has not been found", hash)
+	}
+	receipt := &txReceipt{Hash: hash, Status: TxPending, Penalty: status.Penalty, Result: status.Result}
+	if status.Message != nil {
+		receipt.Err = status.Message.Error
+		receipt.Status = TxRejected
+	}
+	if status.BlockID != "" {
+		receipt.BlockId, _ = strconv.ParseInt(status.BlockID, 10, 64)
+		receipt.Status = TxCommitted
+		if status.Penalty == 1 {
+			receipt.Status = TxPenalized
+		}
+	}
+	receipt.MaxBlockId, err = models.Client.GetMaxBlockID()
+	if err != nil {
+		return nil, err
+	}
+	if receipt.BlockId > 0 && receipt.MaxBlockId >= receipt.BlockId {
+		receipt.Confirmations = receipt.MaxBlockId - receipt.BlockId + 1
+	}
+	return receipt, nil
+}
+
+// waitTxReceipt polls the status of the transaction until it is rejected, penalized or has the confirmations
+func waitTxReceipt(hash string, timeout time.Duration, confirmations int64) (*txReceipt, error) {
+	if confirmations < 1 {
+		confirmations = 1
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		receipt, err := getTxReceipt(hash)
+		if err == nil && (receipt.failed() || receipt.Confirmations >= confirmations) {
+			return receipt, nil
+		}
+		if time.Now().After(deadline) {
+			if err != nil {
+				return nil, err
+			}
+			if receipt.final() {
+				return receipt, fmt.Errorf("transaction %s has %d of %d confirmations after %s", hash, receipt.Confirmations, confirmations, timeout)
+			}
+			return receipt, fmt.Errorf("transaction %s isn't in a block after %s", hash, timeout)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// printTxReceipt prints the receipt and returns a Rejected error if the transaction failed
+func printTxReceipt(receipt *txReceipt, action string) error {
+	err := printResult(receipt)
+	if err != nil {
+		return err
+	}
+	if receipt.failed() {
+		return clierr.New(clierr.Rejected, "%s Rejected: %s", action, receipt.Err)
+	}
+	return nil
+}
+
+// confirmTx waits for the confirmations of a transaction sent by callContract or callUtxo
+func confirmTx(hash, action string) error {
+	receipt, err := waitTxReceipt(hash, txTimeout, txConfirmations)
+	if err != nil {
+		if receipt != nil {
+			if printErr := printResult(receipt); printErr != nil {
+				return printErr
+			}
+		}
+		return clierr.Wrap(clierr.RPC, err, "Wait Transaction Failed")
+	}
+	return printTxReceipt(receipt, action)
+}
+
+// checkAsyncFlags checks --async and --confirmations of callContract and callUtxo
+func checkAsyncFlags() error {
+	if txConfirmations < 0 {
+		return clierr.New(clierr.Argument, "confirmations can't be negative")
+	}
+	if txAsync && txConfirmations > 0 {
+		return clierr.New(clierr.Argument, "--async and --confirmations can't be used together")
+	}
+	return nil
+}
+
+// sendTxAsync signs the transaction with the key of the config, sends it and prints its hash
+func sendTxAsync(tx *types.SmartTransaction) error {
+	privateKey, err := configPrivateKey()
+	if err != nil {
+		return err
+	}
+	networkId, err := nodeNetworkId()
+	if err != nil {
+		return clierr.Wrap(clierr.RPC, err, "Get Network Failed")
+	}
+	var hash string
+	var data []byte
+	err = withAlgorithm(configAlgorithm(), func() (err error) {
+		hash, data, err = signTx(tx, privateKey, networkId, time.Now().Unix())
+		return err
+	})
+	if err != nil {
+		return clierr.Wrap(clierr.Auth, err, "sign transaction failed")
+	}
+	err = sendTxData(hash, data)
+	if err != nil {
+		return err
+	}
+	return printResult(struct {
+		Hash string `json:"hash"`
+	}{Hash: hash})
+}
+
+// configPrivateKey returns the private key of the config
+func configPrivateKey() ([]byte, error) {
+	if conf.Config.PrivateKey == "" {
+		return nil, clierr.New(clierr.Config, "private key can't not be empty, Please set in the configuration file:%s", conf.Config.ConfigPath)
+	}
+	privateKey, err := hex.DecodeString(conf.Config.PrivateKey)
+	if err != nil {
+		return nil, clierr.Wrap(clierr.Config, err, "private key invalid")
+	}
+	return privateKey, nil
+}
+
+// signTx sets the header of the transaction for the login account and signs it, the hash is hex.
+// The crypto package must be initialized with the algorithms of the private key
+func signTx(tx *types.SmartTransaction, privateKey []byte, networkId, txTime int64) (string, []byte, error) {
+	cnf := models.Client.GetConfig()
+	tx.Header.EcosystemID = cnf.Ecosystem
+	tx.Header.KeyID = cnf.KeyId
+	tx.Header.Time = txTime
+	tx.Header.NetworkID = networkId
+	hash, data, err := txfile.SignTransaction(tx, privateKey)
+	if err != nil {
+		return "", nil, err
+	}
+	return hex.EncodeToString(hash), data, nil
+}
+
+// sendTxData broadcasts the signed transaction
+func sendTxData(hash string, data []byte) error {
+	var sent struct {
+		Hashes map[string]string `json:"hashes"`
+	}
+	err := models.CallRPC("ibax.sendTx", &sent, map[string][]byte{hash: data})
+	if err != nil {
+		return clierr.Wrap(clierr.RPC, err, "Send Transaction Failed")
+	}
+	return nil
+}
+
+// parseTxHash returns the transaction hash as lower case hex without 0x prefix
+func parseTxHash(hash string) (string, error) {
+	hash = strings.ToLower(strings.TrimPrefix(hash, "0x"))
+	data, err := hex.DecodeString(hash)
+	if err != nil || len(data) != 32 {
+		return "", fmt.Errorf("transaction hash [%s] invalid", hash)
+	}
+	return hash, nil
+}
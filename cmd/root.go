@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"github.com/IBAX-io/ibax-cli/conf"
 	"github.com/IBAX-io/ibax-cli/models"
+	"github.com/IBAX-io/ibax-cli/packages/clierr"
 	"github.com/IBAX-io/ibax-cli/packages/consts"
-	log "github.com/sirupsen/logrus"
+	"github.com/IBAX-io/ibax-cli/packages/output"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"os"
 	"path/filepath"
 )
 
@@ -15,12 +17,21 @@ import (
 var rootCmd = &cobra.Command{
 	Use:   "ibax-cli",
 	Short: fmt.Sprintf("IBAX Core RPC Client Version: %s", consts.Version()),
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		_, err := output.Parse(outputFormat)
+		return clierr.Wrap(clierr.Argument, err, "invalid output flag")
+	},
+	SilenceErrors: true,
+	SilenceUsage:  true,
 }
 
 var (
 	buildBranch = ""
 	buildDate   = ""
 	commitHash  = ""
+
+	outputFormat = string(output.JSON)
+	profileName  string
 )
 
 func init() {
@@ -31,6 +42,12 @@ func init() {
 		addressToKeyIdCmd,
 		publicKeyToAddressCmd,
 		infoCmd,
+		accountImportCmd,
+		accountExportCmd,
+		accountPasswdCmd,
+		accountDeriveCmd,
+		accountRecoverCmd,
+		accountHistoryCmd,
 	)
 	for _, subCommand := range accountCmd.Commands() {
 		for k, v := range subCommand.SuggestFor {
@@ -45,6 +62,15 @@ func init() {
 		completionCmd,
 		consoleCmd,
 		accountCmd,
+		profileCmd,
+		txCmd,
+		batchCmd,
+		utxoCmd,
+		addressBookCmd,
+		blockCmd,
+		watchCmd,
+		cacheCmd,
+		tableCmd,
 	)
 
 	initCmdList()
@@ -65,20 +91,53 @@ func init() {
 	cmdFlags.StringVar(&conf.Config.ConfigPath, "path", defaultConfigPath(), "filepath to config.yml")
 	cmdFlags.StringVar(&conf.Config.RpcConnect, "rcpConnect", consts.DefaultConnect, "Send commands to node running on <connect>")
 	cmdFlags.IntVar(&conf.Config.RpcPort, "rpcPort", consts.DefaultPort, "Connect to JSON-RPC on <port>")
+	cmdFlags.StringVar(&profileName, "profile", "", "profile of config.yml to use (default the active profile)")
+	cmdFlags.StringVarP(&outputFormat, "output", "o", outputFormat, fmt.Sprintf("Output format (%s)", output.Names()))
 
 	conf.SetDefaultConfig()
 	viper.BindPFlags(cmdFlags)
+	argumentErrors(rootCmd)
 	models.InitGlobalCmd(rootCmd)
+	models.PrintError = printError
 }
 
 func defaultConfigPath() string {
 	return filepath.Join("data", "config.yml")
 }
 
+// printResult writes the command result to stdout in the format chosen by the "output" flag
+func printResult(result any) error {
+	err := output.Print(output.Format(outputFormat), result)
+	return clierr.Wrap(clierr.Unknown, err, "Result marshall Failed")
+}
+
+// printError writes err to stderr, as a json envelope when a json output format is chosen
+func printError(err error) {
+	format := output.Format(outputFormat)
+	clierr.Fprint(os.Stderr, err, format == output.JSON || format == output.PrettyJSON)
+}
+
+// argumentErrors marks the errors of the positional arguments validation and flag parsing as argument errors
+func argumentErrors(cmd *cobra.Command) {
+	cmd.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+		return clierr.Wrap(clierr.Argument, err, "invalid flag")
+	})
+	if validate := cmd.Args; validate != nil {
+		cmd.Args = func(cmd *cobra.Command, args []string) error {
+			return clierr.Wrap(clierr.Argument, validate(cmd, args), "invalid arguments")
+		}
+	}
+	for _, c := range cmd.Commands() {
+		argumentErrors(c)
+	}
+}
+
 // Execute executes rootCmd command.
 // This is called by main.main(). It only needs to happen once to the rootCmd
+// The process exits with the code of the error category, see clierr.Kind
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		log.WithError(err).Fatal("Executing root command")
+		printError(err)
+		os.Exit(clierr.ExitCode(err))
 	}
 }
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/IBAX-io/go-ibax-sdk/packages/pkg/converter"
+	"github.com/IBAX-io/ibax-cli/models"
+	"github.com/IBAX-io/ibax-cli/packages/centrifugo"
+	"github.com/IBAX-io/ibax-cli/packages/clierr"
+	"github.com/IBAX-io/ibax-cli/packages/output"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	// subscribePing is the interval of the pings keeping the connection alive
+	subscribePing = 25 * time.Second
+	// subscribeMaxBackoff limits the wait between two reconnections
+	subscribeMaxBackoff = 30 * time.Second
+)
+
+var (
+	subscribeURL          string
+	subscribeToken        string
+	subscribeChannels     []string
+	subscribeAllEcosystem bool
+	subscribeTimeout      time.Duration
+
+	subscribeCmd = &cobra.Command{
+		Use:   "subscribe",
+		Short: "Print the real-time notifications of the account",
+		Long: `
+Connects to the Centrifugo server of the node, see "getConfig centrifugo", with the login token and prints
+the notifications of the current account until it is stopped with Ctrl-C. The node publishes the unread
+notifications of the roles of the account, the notifications of other ecosystems are skipped unless --all-ecosystems.
+A lost connection is opened again with a growing wait up to 30s, an expired token is refreshed.
+
+Request:
+	--url				(string,optional) Centrifugo server url, default from the node
+	--token				(string,optional) connection token, default the login token
+	--channel			(string,optional) more channels to subscribe, can be repeated
+	--all-ecosystems	(bool,optional) print the notifications of all ecosystems
+	--timeout			(duration,optional) connect and reply timeout, default 10s
+
+Every notification is printed as it comes, with the output format; json lines by default.
+Result:
+	{
+		"time": "str",					(string) receive time, ISO-8601
+		"channel": "str",				(string) channel, client + account address for the account
+		"ecosystem": "str",				(string) ecosystem id of a role notification
+		"role_id": "str",				(string) role id of a role notification
+		"count": n,						(number) unread notifications of the role
+		"data": any						(any) the published data of other notifications
+	}
+`,
+		Args:       cobra.NoArgs,
+		PreRunE:    loginPre,
+		RunE:       subscribe,
+		SuggestFor: []string{"subscribe"},
+		Example:    "./ibax-cli subscribe\n./ibax-cli subscribe --all-ecosystems -o table\n./ibax-cli subscribe --url=ws://127.0.0.1:8000 --channel=news",
+	}
+)
+
+func init() {
+	subscribeCmd.Flags().StringVar(&subscribeURL, "url", "", "Centrifugo server url (default from the node)")
+	subscribeCmd.Flags().StringVar(&subscribeToken, "token", "", "connection token (default the login token)")
+	subscribeCmd.Flags().StringArrayVar(&subscribeChannels, "channel", nil, "more channels to subscribe")
+	subscribeCmd.Flags().BoolVar(&subscribeAllEcosystem, "all-ecosystems", false, "print the notifications of all ecosystems")
+	subscribeCmd.Flags().DurationVar(&subscribeTimeout, "timeout", 10*time.Second, "connect and reply timeout")
+}
+
+// notificationView is a notification printed by subscribe
+type notificationView struct {
+	Time      string          `json:"time"`
+	Channel   string          `json:"channel"`
+	Ecosystem string          `json:"ecosystem,omitempty"`
+	RoleId    string          `json:"role_id,omitempty"`
+	Count     *int64          `json:"count,omitempty"`
+	Data      json.RawMessage `json:"data,omitempty"`
+}
+
+// roleNotification is the record the node publishes for the unread notifications of a role
+type roleNotification struct {
+	Ecosystem string `json:"ecosystem"`
+	RoleId    string `json:"role_id"`
+	Count     int64  `json:"count"`
+}
+
+// newNotificationViews splits a publication of role notifications into a view by role,
+// other publications are a view with their data
+func newNotificationViews(pub *centrifugo.Publication, ecosystem string, received time.Time) []*notificationView {
+	now := received.UTC().Format(time.RFC3339)
+	var roles []roleNotification
+	if err := json.Unmarshal(pub.Data, &roles); err != nil || len(roles) == 0 || roles[0].RoleId == "" {
+		return []*notificationView{{Time: now, Channel: pub.Channel, Data: pub.Data}}
+	}
+	var views []*notificationView
+	for _, r := range roles {
+		if ecosystem != "" && r.Ecosystem != ecosystem {
+			continue
+		}
+		count := r.Count
+		views = append(views, &notificationView{Time: now, Channel: pub.Channel, Ecosystem: r.Ecosystem, RoleId: r.RoleId, Count: &count})
+	}
+	return views
+}
+
+func subscribe(cmd *cobra.Command, params []string) error {
+	if subscribeTimeout <= 0 {
+		return clierr.New(clierr.Argument, "timeout must be positive")
+	}
+	server := subscribeURL
+	if server == "" {
+		result, err := models.Client.GetIBAXConfig("centrifugo")
+		if err != nil {
+			return clierr.Wrap(clierr.RPC, err, "Get Centrifugo Url Failed")
+		}
+		if result == nil || *result == "" {
+			return clierr.New(clierr.NotFound, "the node has no Centrifugo server, set --url")
+		}
+		server = *result
+	}
+	wsURL, err := centrifugo.WebsocketURL(server)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "url invalid")
+	}
+	cnf := models.Client.GetConfig()
+	channels := append([]string{"client" + converter.AddressToString(cnf.KeyId)}, subscribeChannels...)
+	ecosystem := strconv.FormatInt(cnf.Ecosystem, 10)
+	if subscribeAllEcosystem {
+		ecosystem = ""
+	}
+	format := output.Format(outputFormat)
+	if format == "" {
+		format = output.JSON
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	backoff := time.Second
+	for {
+		connected, err := subscribeOnce(ctx, wsURL, channels, func(pub *centrifugo.Publication) error {
+			for _, v := range newNotificationViews(pub, ecosystem, time.Now()) {
+				if err := output.Print(format, v); err != nil {
+					return clierr.Wrap(clierr.Unknown, err, "Result marshall Failed")
+				}
+			}
+			return nil
+		})
+		if ctx.Err() != nil {
+			return nil
+		}
+		var cerr *clierr.Error
+		if errors.As(err, &cerr) {
+			return err
+		}
+		var serverErr *centrifugo.Error
+		if errors.As(err, &serverErr) {
+			if serverErr.Code != centrifugo.ErrTokenExpired || subscribeToken != "" {
+				return clierr.Wrap(clierr.Auth, err, "Subscribe Refused")
+			}
+			if err := models.RefreshToken(); err != nil {
+				return err
+			}
+		}
+		if connected {
+			backoff = time.Second
+		}
+		log.Warnf("subscribe %s: %s, reconnecting in %s", wsURL, err, backoff)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > subscribeMaxBackoff {
+			backoff = subscribeMaxBackoff
+		}
+	}
+}
+
+// subscribeOnce connects and calls fn with the publications until the connection fails or ctx is done,
+// connected reports whether the subscriptions succeeded
+func subscribeOnce(ctx context.Context, wsURL string, channels []string, fn func(*centrifugo.Publication) error) (connected bool, err error) {
+	token := subscribeToken
+	if token == "" {
+		token = models.Client.GetConfig().Token
+	}
+	client, err := centrifugo.Dial(wsURL, token, subscribeTimeout)
+	if err != nil {
+		return false, err
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(subscribePing)
+		defer ticker.Stop()
+		defer client.Close()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := client.Ping(); err != nil {
+					return
+				}
+			}
+		}
+	}()
+	for _, channel := range channels {
+		if err = client.Subscribe(channel, subscribeTimeout); err != nil {
+			return false, err
+		}
+	}
+	log.Infof("subscribed to %v", channels)
+	for {
+		pub, err := client.Read(subscribePing + subscribeTimeout)
+		if err != nil {
+			return true, fmt.Errorf("connection lost: %w", err)
+		}
+		if err = fn(pub); err != nil {
+			return true, err
+		}
+	}
+}
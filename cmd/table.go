@@ -0,0 +1,309 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/IBAX-io/go-ibax-sdk/packages/request"
+	"github.com/IBAX-io/ibax-cli/models"
+	"github.com/IBAX-io/ibax-cli/packages/clierr"
+	"github.com/IBAX-io/ibax-cli/packages/parameter"
+	"github.com/IBAX-io/ibax-cli/packages/tabledump"
+	"github.com/spf13/cobra"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// tableDumpMaxPage is the most rows getList returns
+const tableDumpMaxPage = 100
+
+var (
+	tableDumpFormat  string
+	tableDumpOut     string
+	tableDumpWhere   string
+	tableDumpColumns string
+	tableDumpSync    bool
+	tableDumpSyncBy  string
+	tableDumpPage    int
+	tableDumpTable   string
+
+	tableCmd = &cobra.Command{
+		Use:   "table",
+		Short: "Export the tables of the ecosystem",
+	}
+
+	tableDumpCmd = &cobra.Command{
+		Use:   "dump [TableName]",
+		Short: "write all rows of a table to csv, ndjson or sqlite",
+		Long: `
+Request:
+	TableName		(string) Table name
+	--format		(string,optional) csv, ndjson or sqlite, default from the extension of --file: .csv, .db, .sqlite, otherwise ndjson
+	--file			(string,optional) output file, default stdout, required by sqlite and --sync
+	--where			(string,optional) query conditions of getList, {"id":{"$gt":2},"name":{"$eq":"john"}}
+	--columns		(string,optional) comma-separated columns, default all columns of getTable
+	--sync			(bool,optional) append only the rows after the previous dump of the file
+	--sync-by		(string,optional) column the rows are ordered and synced by, default id
+	--page			(number,optional) rows of each getList request, at most 100
+	--sqlite-table	(string,optional) table of the sqlite database, default the table name without the ecosystem
+
+Requests the rows with getList ordered by the --sync-by column and id, each page after the last row of the previous one,
+so that rows inserted during the dump don't shift the pages. The column types of getTable convert the values,
+bytea is hex in csv and ndjson and a BLOB in sqlite, NULL is null, and an empty field in csv.
+
+The position of the dump is saved after every page, in <file>.sync.json or in the ibax_sync table of the sqlite database.
+--sync continues from it with the same where, columns and sync column; the --sync-by column has to grow
+with every insert or update of a row, like id or an update time. A row updated after the dump is written again:
+sqlite replaces it by id, csv and ndjson append it.
+
+Returns a json object when the rows are written to a file
+Result:
+	{
+		"table": "str",			(string) table name
+		"format": "str",		(string) csv || ndjson || sqlite
+		"file": "str",			(string) output file
+		"rows": n,				(number) rows written by this dump
+		"total": n,				(number) rows written since the first dump of the file
+		"sync_by": "str",		(string) sync column
+		"value": "str",			(string) sync column value of the last row
+		"id": n					(number) id of the last row
+	}
+`,
+		Args:       cobra.ExactArgs(1),
+		PreRunE:    loginPre,
+		RunE:       tableDump,
+		SuggestFor: []string{"dump"},
+		Example:    "./ibax-cli table dump @1keys --file=keys.csv\n./ibax-cli table dump members --file=ecosystem.db --sync\n./ibax-cli table dump history --where='{\"amount\":{\"$gt\":0}}' --columns=sender_id,recipient_id,amount > history.ndjson",
+	}
+)
+
+func init() {
+	tableCmd.AddCommand(
+		tableDumpCmd,
+	)
+	for _, subCommand := range tableCmd.Commands() {
+		for k, v := range subCommand.SuggestFor {
+			subCommand.SuggestFor[k] = tableCmd.Use + " " + v
+			models.AddWordsCompletions(subCommand.SuggestFor)
+		}
+	}
+	cmdFlags := tableDumpCmd.Flags()
+	cmdFlags.StringVar(&tableDumpFormat, "format", "", "csv, ndjson or sqlite (default from the file extension, otherwise ndjson)")
+	cmdFlags.StringVarP(&tableDumpOut, "file", "f", "", "output file (default stdout)")
+	cmdFlags.StringVarP(&tableDumpWhere, "where", "w", "", "query conditions of getList")
+	cmdFlags.StringVarP(&tableDumpColumns, "columns", "c", "", "comma-separated columns (default all)")
+	cmdFlags.BoolVar(&tableDumpSync, "sync", false, "append only the rows after the previous dump of the file")
+	cmdFlags.StringVar(&tableDumpSyncBy, "sync-by", "id", "column the rows are ordered and synced by")
+	cmdFlags.IntVar(&tableDumpPage, "page", tableDumpMaxPage, "rows of each getList request")
+	cmdFlags.StringVar(&tableDumpTable, "sqlite-table", "", "table of the sqlite database (default the table name without the ecosystem)")
+}
+
+type tableDumpResult struct {
+	Table  string `json:"table"`
+	Format string `json:"format"`
+	File   string `json:"file"`
+	Rows   int64  `json:"rows"`
+	Total  int64  `json:"total"`
+	SyncBy string `json:"sync_by"`
+	Value  string `json:"value"`
+	Id     int64  `json:"id"`
+}
+
+var tableEcosystemPrefix = regexp.MustCompile(`^@\d+`)
+
+// tableDumpColumnList returns the id and the requested columns of the table with their types,
+// all columns in name order when none are requested. The sync column is added when it isn't requested.
+func tableDumpColumnList(table map[string]string, requested, syncBy string) ([]tabledump.Column, error) {
+	var names []string
+	if requested == "" {
+		for name := range table {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	} else {
+		for _, name := range strings.Split(requested, ",") {
+			names = append(names, strings.ToLower(strings.TrimSpace(name)))
+		}
+	}
+	names = append(names, syncBy)
+
+	columns := []tabledump.Column{{Name: "id", Type: "number"}}
+	seen := map[string]bool{"id": true}
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		typ, ok := table[name]
+		if !ok {
+			return nil, fmt.Errorf("column [%s] not found", name)
+		}
+		seen[name] = true
+		columns = append(columns, tabledump.Column{Name: name, Type: typ})
+	}
+	return columns, nil
+}
+
+// tableDumpCursor returns the where of the rows after the state, combined with the where of the flag
+func tableDumpCursor(where string, state *tabledump.State) (string, error) {
+	var cursor any
+	if state.SyncBy == "id" {
+		cursor = map[string]any{"id": map[string]any{"$gt": state.Id}}
+	} else {
+		cursor = map[string]any{"$or": []any{
+			map[string]any{state.SyncBy: map[string]any{"$gt": state.Value}},
+			map[string]any{"$and": []any{
+				map[string]any{state.SyncBy: map[string]any{"$eq": state.Value}},
+				map[string]any{"id": map[string]any{"$gt": state.Id}},
+			}},
+		}}
+	}
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	if where == "" {
+		return string(data), nil
+	}
+	// the where of the flag is a json object checked by tableDump, the node parses it
+	return fmt.Sprintf(`{"$and":[%s,%s]}`, where, data), nil
+}
+
+func tableDump(cmd *cobra.Command, params []string) error {
+	args := parameter.New(params)
+	tableName, err := args.Set(0, true).String()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "tableName invalid")
+	}
+	format, err := tabledump.ParseFormat(tableDumpFormat, tableDumpOut)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "format invalid")
+	}
+	if tableDumpOut == "" && (format == tabledump.SQLite || tableDumpSync) {
+		return clierr.New(clierr.Argument, "--file is required by sqlite and --sync")
+	}
+	if tableDumpPage < 1 || tableDumpPage > tableDumpMaxPage {
+		return clierr.New(clierr.Argument, "page [%d] invalid, 1 to %d", tableDumpPage, tableDumpMaxPage)
+	}
+	if tableDumpWhere != "" {
+		var where map[string]any
+		if err = json.Unmarshal([]byte(tableDumpWhere), &where); err != nil || where == nil {
+			return clierr.New(clierr.Argument, "where [%s] invalid, a json object like {\"id\":{\"$gt\":2}}", tableDumpWhere)
+		}
+	}
+	sqliteTable := tableDumpTable
+	if sqliteTable == "" {
+		sqliteTable = tableEcosystemPrefix.ReplaceAllString(tableName, "")
+	}
+
+	table, err := models.Client.GetTable(tableName)
+	if err != nil {
+		return clierr.Wrap(clierr.RPC, err, "get table Failed")
+	}
+	if table == nil {
+		return clierr.New(clierr.NotFound, "table [%s] not found", tableName)
+	}
+	columnTypes := make(map[string]string, len(table.Columns))
+	for _, c := range table.Columns {
+		columnTypes[strings.ToLower(c.Name)] = c.Type
+	}
+	syncBy := strings.ToLower(strings.TrimSpace(tableDumpSyncBy))
+	if typ := columnTypes[syncBy]; syncBy != "id" && (typ == "" || typ == "json" || typ == "bytea") {
+		return clierr.New(clierr.Argument, "sync-by [%s] invalid, id or a column that isn't json or bytea", tableDumpSyncBy)
+	}
+	columns, err := tableDumpColumnList(columnTypes, tableDumpColumns, syncBy)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "columns invalid")
+	}
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.Name
+	}
+
+	var state *tabledump.State
+	if tableDumpSync {
+		state, err = tabledump.ReadState(format, tableDumpOut, sqliteTable)
+		if err != nil {
+			return clierr.Wrap(clierr.Config, err, "read the previous dump Failed")
+		}
+		if state != nil && (state.Table != tableName || state.Where != tableDumpWhere || state.SyncBy != syncBy ||
+			strings.Join(state.Columns, ",") != strings.Join(names, ",")) {
+			return clierr.New(clierr.Argument, "%s is a dump of %s with other flags, sync it with the same where, columns and sync-by or dump it again without --sync",
+				tableDumpOut, state.Table)
+		}
+	}
+	appending := state != nil
+	if state == nil {
+		state = &tabledump.State{Table: tableName, Where: tableDumpWhere, SyncBy: syncBy, Columns: names}
+	}
+
+	w, err := tabledump.Open(format, os.Stdout, tableDumpOut, sqliteTable, columns, appending)
+	if err != nil {
+		return clierr.Wrap(clierr.Config, err, "open "+tableDumpOut+" Failed")
+	}
+	defer w.Close()
+
+	// the order of getList is the ORDER BY of the query, the sync column is a column of getTable
+	order := "id"
+	if syncBy != "id" {
+		order = syncBy + ", id"
+	}
+	// id is always returned by getList
+	requested := strings.Join(names[1:], ",")
+	var rows int64
+	for {
+		form := request.GetList{
+			Name:    tableName,
+			Limit:   tableDumpPage,
+			Order:   order,
+			Columns: requested,
+		}
+		if tableDumpWhere != "" {
+			form.Where = tableDumpWhere
+		}
+		if appending || rows > 0 {
+			if form.Where, err = tableDumpCursor(tableDumpWhere, state); err != nil {
+				return clierr.Wrap(clierr.Unknown, err, "where invalid")
+			}
+		}
+		result, err := models.Client.GetList(form)
+		if err != nil {
+			return clierr.Wrap(clierr.RPC, err, "get list Failed")
+		}
+		if result == nil || len(result.List) == 0 {
+			break
+		}
+		last := result.List[len(result.List)-1]
+		id, err := strconv.ParseInt(last["id"], 10, 64)
+		if err != nil {
+			return clierr.Wrap(clierr.RPC, err, "id of the row invalid")
+		}
+		state.Id = id
+		state.Value = last[syncBy]
+		state.Rows += int64(len(result.List))
+		if err = w.Write(result.List, state); err != nil {
+			return clierr.Wrap(clierr.Unknown, err, "write the rows Failed")
+		}
+		rows += int64(len(result.List))
+		if len(result.List) < tableDumpPage {
+			break
+		}
+	}
+	if err = w.Close(); err != nil {
+		return clierr.Wrap(clierr.Unknown, err, "write the rows Failed")
+	}
+	if tableDumpOut == "" {
+		return nil
+	}
+	return printResult(tableDumpResult{
+		Table:  tableName,
+		Format: string(format),
+		File:   tableDumpOut,
+		Rows:   rows,
+		Total:  state.Rows,
+		SyncBy: syncBy,
+		Value:  state.Value,
+		Id:     state.Id,
+	})
+}
@@ -0,0 +1,402 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/IBAX-io/ibax-cli/conf"
+	"github.com/IBAX-io/ibax-cli/models"
+	"github.com/IBAX-io/ibax-cli/packages/clierr"
+	"github.com/IBAX-io/ibax-cli/packages/parameter"
+	"github.com/IBAX-io/ibax-cli/packages/txfile"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	txBuildFile string
+	txSignFile  string
+	txAccount   string
+	txNetworkId int64
+	txTimeout   time.Duration
+
+	txCmd = &cobra.Command{
+		Use:   "tx",
+		Short: "Build, sign and send a transaction in separate steps",
+		Long: `
+"tx build" writes an unsigned transaction file on a machine connected to the node,
+"tx sign" signs the file with a local key and needs no network, "tx send" broadcasts the signed file.
+The private key never has to be on the networked machine.
+
+The file is json, it shows the contract, the decoded params and the signer for review:
+	{
+		"version": 1,					(number) Format version
+		"type": "contract",				(string) Transaction type
+		"status": "str",				(string) unsigned | signed
+		"contract": "str",				(string) Contract name
+		"contract_id": n,				(number) Contract ID in VM
+		"params": {},					(json object) Contract params, bytes are written as 0x hex
+		"expedite": "str",				(string) Expedite fee
+		"ecosystem_id": n,				(number) Ecosystem id
+		"key_id": n,					(number) Key id of the signer
+		"account": "str",				(string) Account address of the signer
+		"network_id": n,				(number) Network id of the node
+		"time": n,						(number) Unix time of the transaction
+		"expires": "str",				(string) The node rejects the transaction after this time
+		"cryptoer": "str",				(string) Key and Sign Algorithm
+		"hasher": "str",				(string) Hash Algorithm
+		"public_key": "str",			(string) Public key of the signer, set by "tx sign"
+		"payload": "str",				(string) hex msgpack of the transaction
+		"hash": "str",					(string) Transaction hash, set by "tx sign"
+		"signature": "str"				(string) hex signature, set by "tx sign"
+	}
+The payload is the transaction, the other fields are checked against it: an edited file is rejected.
+`,
+	}
+
+	txBuildCmd = &cobra.Command{
+		Use:   "build [ContractName] [Params] [Expedite]",
+		Short: "write an unsigned transaction file",
+		Long: `
+Request:
+	ContractName  		(string) call contract name
+	Params 				(json object,optional) contract params, tagged values are decoded like callContract
+	Expedite			(string,optional) expedite, IBAX or with unit: "0.5IBAX"
+
+The params are converted to the types of the contract fields, given by getContractInfo.
+The transaction is signed by --account, the login account by default, and written to --file.
+` + txFileResultDoc,
+		Args:       cobra.RangeArgs(1, 3),
+		PreRunE:    loginPre,
+		RunE:       txBuild,
+		SuggestFor: []string{"build"},
+		Example:    `./ibax-cli tx build @1TokensSend '{"Recipient": "0666-7782-xxxx-xxxx-3160", "Amount": "1000000000000"}' --account=1536-0127-xxxx-xxxx-8157 -f tx.json`,
+	}
+
+	txSignCmd = &cobra.Command{
+		Use:   "sign [TxFile]",
+		Short: "sign a transaction file offline",
+		Long: `
+Request:
+	TxFile				(string) unsigned transaction file
+
+Signs the file with the private key or the keystore of the config, select another key with --profile.
+No node is contacted. The file is rewritten, unless --file is given.
+` + txFileResultDoc,
+		Args:       cobra.ExactArgs(1),
+		PreRunE:    loadConfigPre,
+		RunE:       txSign,
+		SuggestFor: []string{"sign"},
+		Example:    `./ibax-cli tx sign tx.json --profile=treasury`,
+	}
+
+	txSendCmd = &cobra.Command{
+		Use:   "send [TxFile]",
+		Short: "broadcast a signed transaction file",
+		Long: `
+Request:
+	TxFile				(string) signed transaction file
+
+Sends the transaction and waits until it is in a block, at most --timeout.
+` + txReceiptDoc,
+		Args:       cobra.ExactArgs(1),
+		PreRunE:    loginPre,
+		RunE:       txSend,
+		SuggestFor: []string{"send"},
+		Example:    `./ibax-cli tx send tx.json`,
+	}
+
+	txStatusCmd = &cobra.Command{
+		Use:   "status [Hash]",
+		Short: "get the status of a transaction",
+		Long: `
+Request:
+	Hash				(string) transaction hash, returned by "tx send", "callContract --async" or "callUtxo --async"
+
+Gets the status of the transaction once, a transaction that isn't in a block yet is pending.
+` + txReceiptDoc,
+		Args:       cobra.ExactArgs(1),
+		PreRunE:    loginPre,
+		RunE:       txStatus,
+		SuggestFor: []string{"status"},
+		Example:    `./ibax-cli tx status 0x5a3c...e1f0`,
+	}
+
+	txWaitCmd = &cobra.Command{
+		Use:   "wait [Hash]",
+		Short: "wait for the confirmations of a transaction",
+		Long: `
+Request:
+	Hash				(string) transaction hash, returned by "tx send", "callContract --async" or "callUtxo --async"
+
+Polls the status of the transaction every second until it is rejected, penalized, or --confirmations blocks,
+its own block included, are on top of it. Fails after --timeout.
+` + txReceiptDoc,
+		Args:       cobra.ExactArgs(1),
+		PreRunE:    loginPre,
+		RunE:       txWait,
+		SuggestFor: []string{"wait"},
+		Example:    `./ibax-cli tx wait 0x5a3c...e1f0 --confirmations=6 --timeout=2m`,
+	}
+)
+
+func init() {
+	txCmd.AddCommand(
+		txBuildCmd,
+		txSignCmd,
+		txSendCmd,
+		txStatusCmd,
+		txWaitCmd,
+	)
+	for _, subCommand := range txCmd.Commands() {
+		for k, v := range subCommand.SuggestFor {
+			subCommand.SuggestFor[k] = txCmd.Use + " " + v
+			models.AddWordsCompletions(subCommand.SuggestFor)
+		}
+	}
+
+	txBuildCmd.Flags().StringVarP(&txBuildFile, "file", "f", "tx.json", "transaction file to write")
+	txBuildCmd.Flags().StringVar(&txAccount, "account", "", "account address, @alias or key id of the signer (default the login account)")
+	txBuildCmd.Flags().Int64Var(&txNetworkId, "networkId", 0, "network id (default the network id of the node)")
+	txSignCmd.Flags().StringVarP(&txSignFile, "file", "f", "", "signed transaction file to write (default the input file)")
+	txSendCmd.Flags().DurationVar(&txTimeout, "timeout", time.Minute, "time to wait for the transaction status")
+	txWaitCmd.Flags().DurationVar(&txTimeout, "timeout", time.Minute, "time to wait for the confirmations")
+	txWaitCmd.Flags().Int64Var(&txWaitConfirmations, "confirmations", 1, "number of blocks on top of the transaction, its own block included")
+}
+
+const txFileResultDoc = `
+Returns a json object of the written file.
+Result:
+	{
+		"file": "str",				(string) Path of the transaction file
+		"status": "str",			(string) unsigned | signed
+		"account": "str",			(string) Account address of the signer
+		"expires": "str",			(string) The node rejects the transaction after this time
+		"hash": "str"				(string,optional) Transaction hash, set by "tx sign"
+	}
+`
+
+// txFileResult is the result of tx build and tx sign
+type txFileResult struct {
+	File    string `json:"file"`
+	Status  string `json:"status"`
+	Account string `json:"account"`
+	Expires string `json:"expires"`
+	Hash    string `json:"hash,omitempty"`
+}
+
+func txBuild(cmd *cobra.Command, params []string) error {
+	args := parameter.New(params)
+	contractName, err := args.Set(0, true).String()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "ContractName invalid")
+	}
+	contractParamsStr, err := args.Set(1, false).String()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "Params invalid")
+	}
+	expedite, err := args.Set(2, false).String()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "Expedite invalid")
+	}
+	if _, err := os.Stat(txBuildFile); err == nil {
+		return clierr.New(clierr.Argument, "transaction file %s exists", txBuildFile)
+	}
+
+	cnf := models.Client.GetConfig()
+	keyId := cnf.KeyId
+	if txAccount != "" {
+		keyId, err = parseKeyId(txAccount)
+		if err != nil {
+			return clierr.Wrap(clierr.Argument, err, "account invalid")
+		}
+	}
+
+	contract, err := models.Client.GetContract(contractName)
+	if err != nil {
+		return clierr.Wrap(clierr.RPC, err, "Get GetContract Failed")
+	}
+	if contract == nil {
+		return clierr.New(clierr.NotFound, "Get GetContract Result Empty")
+	}
+	rawParams, err := decodeJSONParams(contractParamsStr)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "Params invalid")
+	}
+	err = decodeParamTags(rawParams)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "Params invalid")
+	}
+	contractParams, err := convertContractParams(contract.Fields, rawParams)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "Params invalid")
+	}
+	expedite, err = parseExpedite(expedite)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "Expedite invalid")
+	}
+
+	networkId := txNetworkId
+	if networkId == 0 {
+		networkId, err = nodeNetworkId()
+		if err != nil {
+			return clierr.Wrap(clierr.RPC, err, "Get Network Failed")
+		}
+	}
+
+	header := types.Header{
+		ID:          int(contract.ID),
+		EcosystemID: cnf.Ecosystem,
+		KeyID:       keyId,
+		Time:        time.Now().Unix(),
+		NetworkID:   networkId,
+	}
+	f, err := txfile.New(contract.Name, header, contractParams, expedite)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "build transaction failed")
+	}
+	algo := configAlgorithm()
+	f.Cryptoer, f.Hasher = algo.Cryptoer, algo.Hasher
+	err = f.Write(txBuildFile)
+	if err != nil {
+		return clierr.Wrap(clierr.Unknown, err, "save transaction failed")
+	}
+	return printResult(txFileResult{File: txBuildFile, Status: f.Status, Account: f.Account, Expires: f.Expires})
+}
+
+func txSign(cmd *cobra.Command, params []string) error {
+	args := parameter.New(params)
+	filename, err := args.Set(0, true).String()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "TxFile invalid")
+	}
+	f, err := txfile.Read(filename)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "read transaction failed")
+	}
+	if f.Status == txfile.StatusSigned {
+		return clierr.New(clierr.Argument, "transaction file %s is signed already", filename)
+	}
+	if conf.Config.PrivateKey == "" {
+		return clierr.New(clierr.Config, "private key can't not be empty, Please set in the configuration file:%s", conf.Config.ConfigPath)
+	}
+	privateKey, err := hex.DecodeString(conf.Config.PrivateKey)
+	if err != nil {
+		return clierr.Wrap(clierr.Config, err, "private key invalid")
+	}
+	algo := keyAlgorithm{Cryptoer: f.Cryptoer, Hasher: f.Hasher}
+	if err := algo.validate(); err != nil {
+		return clierr.Wrap(clierr.Argument, err, "transaction algorithm invalid")
+	}
+	err = withAlgorithm(algo, func() error {
+		return f.Sign(privateKey)
+	})
+	if err != nil {
+		return clierr.Wrap(clierr.Auth, err, "sign transaction failed")
+	}
+	out := filename
+	if txSignFile != "" {
+		out = txSignFile
+	}
+	err = f.Write(out)
+	if err != nil {
+		return clierr.Wrap(clierr.Unknown, err, "save transaction failed")
+	}
+	return printResult(txFileResult{File: out, Status: f.Status, Account: f.Account, Expires: f.Expires, Hash: f.Hash})
+}
+
+func txSend(cmd *cobra.Command, params []string) error {
+	args := parameter.New(params)
+	filename, err := args.Set(0, true).String()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "TxFile invalid")
+	}
+	f, err := txfile.Read(filename)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "read transaction failed")
+	}
+	algo := keyAlgorithm{Cryptoer: f.Cryptoer, Hasher: f.Hasher}
+	if err := algo.validate(); err != nil {
+		return clierr.Wrap(clierr.Argument, err, "transaction algorithm invalid")
+	}
+	var data []byte
+	err = withAlgorithm(algo, func() (err error) {
+		data, err = f.Data()
+		return err
+	})
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "transaction invalid")
+	}
+
+	err = sendTxData(f.Hash, data)
+	if err != nil {
+		return err
+	}
+	log.Infof("transaction %s is sent, waiting for the status", f.Hash)
+
+	receipt, err := waitTxReceipt(f.Hash, txTimeout, 1)
+	if err != nil {
+		return clierr.Wrap(clierr.RPC, err, "Get Transaction Status Failed")
+	}
+	return printTxReceipt(receipt, "Send Transaction")
+}
+
+func txStatus(cmd *cobra.Command, params []string) error {
+	args := parameter.New(params)
+	hash, err := args.Set(0, true).String()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "Hash invalid")
+	}
+	hash, err = parseTxHash(hash)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "Hash invalid")
+	}
+	receipt, err := getTxReceipt(hash)
+	if err != nil {
+		if strings.Contains(err.Error(), "has not been found") {
+			return clierr.Wrap(clierr.NotFound, err, "Get Transaction Status Failed")
+		}
+		return clierr.Wrap(clierr.RPC, err, "Get Transaction Status Failed")
+	}
+	return printTxReceipt(receipt, "Transaction")
+}
+
+func txWait(cmd *cobra.Command, params []string) error {
+	args := parameter.New(params)
+	hash, err := args.Set(0, true).String()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "Hash invalid")
+	}
+	hash, err = parseTxHash(hash)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "Hash invalid")
+	}
+	if txWaitConfirmations < 1 {
+		return clierr.New(clierr.Argument, "confirmations must be at least 1")
+	}
+	receipt, err := waitTxReceipt(hash, txTimeout, txWaitConfirmations)
+	if err != nil {
+		if receipt != nil {
+			if printErr := printResult(receipt); printErr != nil {
+				return printErr
+			}
+		}
+		return clierr.Wrap(clierr.RPC, err, "Wait Transaction Failed")
+	}
+	return printTxReceipt(receipt, "Transaction")
+}
+
+// nodeNetworkId returns the network id of the node
+func nodeNetworkId() (int64, error) {
+	var network struct {
+		NetworkID string `json:"network_id"`
+	}
+	err := models.CallRPC("net.getNetwork", &network)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(network.NetworkID, 10, 64)
+}
@@ -1,26 +1,35 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"github.com/IBAX-io/go-ibax-sdk/packages/request"
 	"github.com/IBAX-io/ibax-cli/models"
+	"github.com/IBAX-io/ibax-cli/packages/clierr"
 	"github.com/IBAX-io/ibax-cli/packages/parameter"
-	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"strings"
+	"time"
 )
 
+var utxoCmd = &cobra.Command{
+	Use:   "utxo",
+	Short: "UTXO transfers",
+}
+
 var callUtxo = &cobra.Command{
 	Use:   "callUtxo [type] [params] [expedite]",
 	Short: "Call UTXO",
 	Long: `
 Request:
-	type   					(string) Transfer || ContractToUTXO (contract account to utxo account) || UTXOToContract (utxo account to contract account)
+	type   					(string) Transfer || ContractToUTXO (contract account to utxo account) || UTXOToContract (utxo account to contract account),
+							the names with Type prefix are accepted too: TypeTransfer || TypeContractToUTXO || TypeUTXOToContract
 	params  				(json object,optional) uxto params
-		recipient  			(string,optional) recipient address: "xxxx-xxxx-xxxx-xxxx-xxxx"
+		recipient  			(string,optional) recipient address: "xxxx-xxxx-xxxx-xxxx-xxxx" or @alias, required by Transfer
 		comment  			(string,optional) transaction comment
-		amount 				(string) amount
-	expedite 				(string,optional) expedite unit: QIBAX
+		amount 				(string) positive amount, smallest unit or with unit: "1.5IBAX"
+	expedite 				(string,optional) expedite, IBAX or with unit
+
+See also "utxo transfer", "utxo to-contract" and "utxo from-contract".
 
 Returns a json object transaction status information.
 Result:
@@ -30,70 +39,223 @@ Result:
 		"penalty": n,			(number) If transaction execution fails, (0: no penalty 1: penalty)
 		"err": ""				(string, optional) If the execution of the transaction fails, an error text message is returned.
 	}
-`,
-	SuggestFor: []string{"callUtxo " + TypeUTXOToContract, "callUtxo " + TypeTransfer, "callUtxo " + TypeContractToUTXO},
+` + moneyDoc + asyncDoc + dryRunDoc,
+	SuggestFor: []string{
+		"callUtxo " + UtxoTransfer, "callUtxo " + UtxoContractToUTXO, "callUtxo " + UtxoUTXOToContract,
+		"callUtxo " + TypeTransfer, "callUtxo " + TypeContractToUTXO, "callUtxo " + TypeUTXOToContract,
+	},
 	Example: `
 ./ibax-cli callUtxo Transfer '{"recipient": "0666-7782-xxxx-xxxx-3160", "amount": "1", "comment": ""}' '1'
 ./ibax-cli callUtxo ContractToUTXO '{"amount": "1"}' '1'
+./ibax-cli callUtxo TypeTransfer '{"recipient": "0666-7782-xxxx-xxxx-3160", "amount": "1.5IBAX"}' '0.5IBAX'
 ./ibax-cli callUtxo UTXOToContract '{"amount": "1"}' '1'
+./ibax-cli callUtxo TypeTransfer '{"recipient": "0666-7782-xxxx-xxxx-3160", "amount": "1"}' --dry-run
+./ibax-cli callUtxo TypeTransfer '{"recipient": "0666-7782-xxxx-xxxx-3160", "amount": "1"}' --async
 `,
-	Args:   cobra.RangeArgs(2, 3),
-	PreRun: loginPre,
-	Run:    callUtxoCmd,
+	Args:              cobra.RangeArgs(2, 3),
+	ValidArgsFunction: completeUtxoType,
+	PreRunE:           loginPre,
+	RunE:              callUtxoCmd,
+}
+
+var (
+	utxoRecipient string
+	utxoAmount    string
+	utxoComment   string
+	utxoExpedite  string
+
+	utxoTransferCmd = &cobra.Command{
+		Use:   "transfer",
+		Short: "transfer from the UTXO account to the UTXO account of the recipient",
+		Long: `
+Request:
+	--recipient				(string) recipient address: "xxxx-xxxx-xxxx-xxxx-xxxx" or @alias of the address book
+	--amount				(string) positive amount, smallest unit or with unit: "1.5IBAX"
+	--comment				(string,optional) transaction comment
+	--expedite				(string,optional) expedite, IBAX or with unit
+
+Same as "callUtxo Transfer", returns a json object transaction status information.
+` + moneyDoc + asyncDoc + dryRunDoc,
+		Args:       cobra.NoArgs,
+		PreRunE:    loginPre,
+		RunE:       utxoTransfer,
+		SuggestFor: []string{"transfer"},
+		Example:    "./ibax-cli utxo transfer --recipient=0666-7782-xxxx-xxxx-3160 --amount=1.5IBAX --comment=payout",
+	}
+
+	utxoToContractCmd = &cobra.Command{
+		Use:   "to-contract",
+		Short: "move from the UTXO account to the contract account",
+		Long: `
+Request:
+	--amount				(string) positive amount, smallest unit or with unit: "1.5IBAX"
+	--expedite				(string,optional) expedite, IBAX or with unit
+
+Same as "callUtxo UTXOToContract", returns a json object transaction status information.
+` + moneyDoc + asyncDoc + dryRunDoc,
+		Args:       cobra.NoArgs,
+		PreRunE:    loginPre,
+		RunE:       utxoToContract,
+		SuggestFor: []string{"to-contract"},
+		Example:    "./ibax-cli utxo to-contract --amount=1.5IBAX",
+	}
+
+	utxoFromContractCmd = &cobra.Command{
+		Use:   "from-contract",
+		Short: "move from the contract account to the UTXO account",
+		Long: `
+Request:
+	--amount				(string) positive amount, smallest unit or with unit: "1.5IBAX"
+	--expedite				(string,optional) expedite, IBAX or with unit
+
+Same as "callUtxo ContractToUTXO", returns a json object transaction status information.
+` + moneyDoc + asyncDoc + dryRunDoc,
+		Args:       cobra.NoArgs,
+		PreRunE:    loginPre,
+		RunE:       utxoFromContract,
+		SuggestFor: []string{"from-contract"},
+		Example:    "./ibax-cli utxo from-contract --amount=1.5IBAX",
+	}
+)
+
+func init() {
+	utxoCmd.AddCommand(utxoTransferCmd, utxoToContractCmd, utxoFromContractCmd, utxoBulkTransferCmd)
+	for _, subCommand := range utxoCmd.Commands() {
+		for k, v := range subCommand.SuggestFor {
+			subCommand.SuggestFor[k] = utxoCmd.Use + " " + v
+			models.AddWordsCompletions(subCommand.SuggestFor)
+		}
+	}
+	utxoTransferCmd.Flags().StringVar(&utxoRecipient, "recipient", "", "recipient address or @alias")
+	utxoTransferCmd.Flags().StringVar(&utxoComment, "comment", "", "transaction comment")
+	utxoTransferCmd.MarkFlagRequired("recipient")
+	for _, c := range []*cobra.Command{utxoTransferCmd, utxoToContractCmd, utxoFromContractCmd} {
+		c.Flags().StringVar(&utxoAmount, "amount", "", "amount, smallest unit or with unit")
+		c.Flags().StringVar(&utxoExpedite, "expedite", "", "expedite, IBAX or with unit")
+		c.MarkFlagRequired("amount")
+	}
+	utxoBulkTransferCmd.Flags().IntVar(&bulkWorkers, "workers", 4, "number of transfers sent at the same time")
+	utxoBulkTransferCmd.Flags().Float64Var(&bulkRate, "rate", 10, "transfers sent per second at most, 0 no limit")
+	utxoBulkTransferCmd.Flags().StringVar(&bulkState, "state", "", "state file to resume from (default <CsvFile>.state)")
+	utxoBulkTransferCmd.Flags().StringVar(&bulkResult, "result", "", "result csv file to write (default <CsvFile>.result.csv)")
+	utxoBulkTransferCmd.Flags().StringVar(&bulkExpedite, "expedite", "", "expedite of every transfer, IBAX or with unit")
+	utxoBulkTransferCmd.Flags().DurationVar(&bulkTimeout, "timeout", time.Minute, "time to wait for the block of every transfer")
 }
 
 const (
 	TypeTransfer       = "TypeTransfer"
 	TypeContractToUTXO = "TypeContractToUTXO"
 	TypeUTXOToContract = "TypeUTXOToContract"
+
+	// the names of the documentation
+	UtxoTransfer       = "Transfer"
+	UtxoContractToUTXO = "ContractToUTXO"
+	UtxoUTXOToContract = "UTXOToContract"
 )
 
-func callUtxoCmd(cmd *cobra.Command, params []string) {
+func callUtxoCmd(cmd *cobra.Command, params []string) error {
 	args := parameter.New(params)
 	utxoTypeStr, err := args.Set(0, true).String()
 	if err != nil {
-		log.Infof("Type invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "Type invalid")
 	}
 	paramsStr, err := args.Set(1, true).String()
 	if err != nil {
-		log.Infof("Params invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "Params invalid")
 	}
 	expedite, err := args.Set(2, false).String()
 	if err != nil {
-		log.Infof("Params invalid:%s", err.Error())
-		return
+		return clierr.Wrap(clierr.Argument, err, "Params invalid")
+	}
+	// the numbers are kept as written, an amount or a key id doesn't fit in a float64
+	raw, err := decodeJSONParams(paramsStr)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "Params JSON Parsing Failed")
+	}
+	utxoParams := request.MapParams(raw)
+	if utxoParams == nil {
+		utxoParams = make(request.MapParams)
+	}
+	return sendUtxo(utxoTypeStr, utxoParams, expedite)
+}
+
+func utxoTransfer(cmd *cobra.Command, params []string) error {
+	return sendUtxo(UtxoTransfer, request.MapParams{"recipient": utxoRecipient, "amount": utxoAmount, "comment": utxoComment}, utxoExpedite)
+}
+
+func utxoToContract(cmd *cobra.Command, params []string) error {
+	return sendUtxo(UtxoUTXOToContract, request.MapParams{"amount": utxoAmount}, utxoExpedite)
+}
+
+func utxoFromContract(cmd *cobra.Command, params []string) error {
+	return sendUtxo(UtxoContractToUTXO, request.MapParams{"amount": utxoAmount}, utxoExpedite)
+}
+
+// sendUtxo checks the params of the utxo type and sends the transaction like callUtxo
+func sendUtxo(utxoTypeStr string, utxoParams request.MapParams, expedite string) error {
+	if err := checkAsyncFlags(); err != nil {
+		return err
+	}
+	utxoType, err := parseUtxoType(utxoTypeStr)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "Type invalid")
+	}
+	expedite, err = parseExpedite(expedite)
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "Expedite invalid")
 	}
-	var utxoParams request.MapParams
-	err = json.Unmarshal([]byte(paramsStr), &utxoParams)
+	if dryRun {
+		return utxoDryRun(utxoTypeStr, utxoType, utxoParams, expedite)
+	}
+	tx, err := newUtxoTx(utxoType, utxoParams, expedite)
 	if err != nil {
-		log.Infof("Params JSON Parsing Failed: %s", err.Error())
-		return
+		return err
 	}
-	var utxoType request.UtxoType
-	switch utxoTypeStr {
-	case TypeTransfer:
-		utxoType = request.TypeTransfer
-	case TypeContractToUTXO:
-		utxoType = request.TypeContractToUTXO
-	case TypeUTXOToContract:
-		utxoType = request.TypeUTXOToContract
+	if txAsync {
+		return sendTxAsync(tx)
 	}
 
 	result, err := models.Client.AutoCallUtxo(utxoType, &utxoParams, expedite)
 	if err != nil {
-		log.Infof("Call UTXO Failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.RPC, err, "Call UTXO Failed")
 	}
 	if result == nil {
-		log.Info("Call UTXO Result Empty")
-		return
+		return clierr.New(clierr.NotFound, "Call UTXO Result Empty")
+	}
+	if txConfirmations >= 1 && result.Penalty == 0 && result.Err == "" {
+		return confirmTx(result.Hash, "Call UTXO")
 	}
-	str, err := json.MarshalIndent(*result, "", "    ")
+	err = printResult(*result)
 	if err != nil {
-		fmt.Printf("Result marshall Failed:%s\n", err.Error())
-		return
+		return err
+	}
+	if result.Penalty == 1 || result.Err != "" {
+		return clierr.New(clierr.Rejected, "Call UTXO Rejected: %s", result.Err)
+	}
+	return nil
+}
+
+// parseUtxoType returns the utxo type of the name, with or without Type prefix, case insensitive
+func parseUtxoType(utxoTypeStr string) (request.UtxoType, error) {
+	switch strings.TrimPrefix(strings.ToLower(utxoTypeStr), "type") {
+	case strings.ToLower(UtxoTransfer):
+		return request.TypeTransfer, nil
+	case strings.ToLower(UtxoContractToUTXO):
+		return request.TypeContractToUTXO, nil
+	case strings.ToLower(UtxoUTXOToContract):
+		return request.TypeUTXOToContract, nil
+	}
+	return 0, fmt.Errorf("utxo type [%s] unknown, %s || %s || %s", utxoTypeStr, UtxoTransfer, UtxoContractToUTXO, UtxoUTXOToContract)
+}
+
+// completeUtxoType completes the type of callUtxo in the shell
+func completeUtxoType(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
-	fmt.Printf("\n%+v\n", string(str))
+	return []string{
+		UtxoTransfer + "\tto the UTXO account of the recipient",
+		UtxoContractToUTXO + "\tcontract account to UTXO account",
+		UtxoUTXOToContract + "\tUTXO account to contract account",
+	}, cobra.ShellCompDirectiveNoFileComp
 }
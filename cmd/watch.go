@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/IBAX-io/go-ibax-sdk/packages/response"
+	"github.com/IBAX-io/ibax-cli/conf"
+	"github.com/IBAX-io/ibax-cli/models"
+	"github.com/IBAX-io/ibax-cli/packages/clierr"
+	"github.com/IBAX-io/ibax-cli/packages/output"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// watchMaxBackoff limits the wait between the retries of a failing node
+const watchMaxBackoff = 30 * time.Second
+
+var (
+	watchFrom       int64
+	watchCheckpoint string
+	watchInterval   time.Duration
+
+	watchCmd = &cobra.Command{
+		Use:   "watch",
+		Short: "Follow the chain and stream the new blocks or transactions",
+		Long: `
+The watch commands poll the latest block of the node and write every new block or matching transaction
+as a json line to stdout, until they are stopped with Ctrl-C. Warnings go to stderr.
+
+The last block written is saved to --checkpoint, by default watch-blocks.json or watch-txs.json in the data directory.
+A restart resumes after the checkpoint, --from starts at a block instead. Without checkpoint and --from only
+the blocks after the latest block are written.
+Blocks missed between two polls are all written in order. When the node fails the watch retries with a growing wait,
+and when the node is behind the checkpoint the watch waits for it. The checkpoint block is requested again with
+every new block, when its hash changed after a rollback that block and the following ones are written again.
+A rollback of the blocks before the checkpoint block isn't detected.
+`,
+	}
+
+	watchBlocksCmd = &cobra.Command{
+		Use:   "blocks",
+		Short: "stream the new blocks",
+		Long: `
+Request:
+	--from				(number,optional) first block to write
+	--checkpoint		(string,optional) checkpoint file
+	--interval			(duration,optional) poll interval, default 1s
+
+Writes a json line for every block with its transactions, see "block get".
+`,
+		Args:       cobra.NoArgs,
+		PreRunE:    loadConfigPre,
+		RunE:       watchBlocks,
+		SuggestFor: []string{"blocks"},
+		Example:    "./ibax-cli watch blocks\n./ibax-cli watch blocks --from=1000 --checkpoint=blocks.json > blocks.ndjson",
+	}
+
+	watchTxsCmd = &cobra.Command{
+		Use:   "txs",
+		Short: "stream the new transactions",
+		Long: `
+Request:
+	--from				(number,optional) first block to read
+	--checkpoint		(string,optional) checkpoint file
+	--interval			(duration,optional) poll interval, default 1s
+	--contract			(string,optional) contract name, with or without the ecosystem prefix
+	--account			(string,optional) signer, account address, @alias or key id
+	--type				(string,optional) transaction type, see "block txs"
+
+Writes a json line for every matching transaction, see "block txs".
+`,
+		Args:       cobra.NoArgs,
+		PreRunE:    loadConfigPre,
+		RunE:       watchTxs,
+		SuggestFor: []string{"txs"},
+		Example:    "./ibax-cli watch txs --contract=@1TokensSend --account=@treasury",
+	}
+)
+
+func init() {
+	watchCmd.AddCommand(
+		watchBlocksCmd,
+		watchTxsCmd,
+	)
+	for _, subCommand := range watchCmd.Commands() {
+		for k, v := range subCommand.SuggestFor {
+			subCommand.SuggestFor[k] = watchCmd.Use + " " + v
+			models.AddWordsCompletions(subCommand.SuggestFor)
+		}
+		subCommand.Flags().Int64Var(&watchFrom, "from", 0, "first block, default after the checkpoint")
+		subCommand.Flags().StringVar(&watchCheckpoint, "checkpoint", "", "checkpoint file (default watch-<blocks|txs>.json in the data directory)")
+		subCommand.Flags().DurationVar(&watchInterval, "interval", time.Second, "poll interval")
+	}
+	watchTxsCmd.Flags().StringVar(&blockTxsFilter.contract, "contract", "", "contract name")
+	watchTxsCmd.Flags().StringVar(&blockTxsFilter.account, "account", "", "signer, account address, @alias or key id")
+	watchTxsCmd.Flags().StringVar(&blockTxsFilter.txType, "type", "", "transaction type, name or number")
+}
+
+// chainCheckpoint is the last block written by a watch
+type chainCheckpoint struct {
+	BlockId int64  `json:"block_id"`
+	Hash    string `json:"hash"`
+	Time    string `json:"time"`
+}
+
+func readCheckpoint(filename string) (*chainCheckpoint, error) {
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cp := &chainCheckpoint{}
+	if err = json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("checkpoint %s: %w", filename, err)
+	}
+	return cp, nil
+}
+
+// writeCheckpoint replaces the checkpoint file, so that a crash leaves the previous one
+func writeCheckpoint(filename string, cp *chainCheckpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	tmp := filename + ".tmp"
+	if err = os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
+
+func watchBlocks(cmd *cobra.Command, params []string) error {
+	nodes := honorNodes()
+	return watchChain("blocks", func(info *response.BlockDetailedInfo) error {
+		return output.Fprint(os.Stdout, output.JSON, newBlockView(info, nodes, true))
+	})
+}
+
+func watchTxs(cmd *cobra.Command, params []string) error {
+	match, err := txFilter()
+	if err != nil {
+		return clierr.Wrap(clierr.Argument, err, "filter invalid")
+	}
+	return watchChain("txs", func(info *response.BlockDetailedInfo) error {
+		for _, tx := range newTxViews(info) {
+			if !match(tx) {
+				continue
+			}
+			if err := output.Fprint(os.Stdout, output.JSON, tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// watchChain calls emit with every new block in order and saves the checkpoint after it, until it is interrupted
+func watchChain(name string, emit func(*response.BlockDetailedInfo) error) error {
+	if watchInterval <= 0 {
+		return clierr.New(clierr.Argument, "interval must be positive")
+	}
+	checkpointFile := watchCheckpoint
+	if checkpointFile == "" {
+		checkpointFile = filepath.Join(conf.Config.DirPathConf.DataDir, "watch-"+name+".json")
+	}
+	cp, err := readCheckpoint(checkpointFile)
+	if err != nil {
+		return clierr.Wrap(clierr.Config, err, "loading checkpoint")
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var next int64
+	switch {
+	case watchFrom > 0:
+		next = watchFrom
+	case cp != nil:
+		next = cp.BlockId + 1
+	}
+	backoff := watchInterval
+	behind := false
+	for {
+		err = func() error {
+			maxBlock, err := models.Client.GetMaxBlockID()
+			if err != nil {
+				return fmt.Errorf("get max block: %w", err)
+			}
+			if next == 0 {
+				next = maxBlock + 1
+			}
+			if maxBlock < next-1 {
+				if !behind {
+					log.Warnf("the latest block %d of the node is behind block %d, waiting", maxBlock, next-1)
+					behind = true
+				}
+				return nil
+			}
+			behind = false
+			if maxBlock < next {
+				return nil
+			}
+			// the checkpoint block is requested again with the new blocks, its hash changes after a rollback
+			start := next
+			if cp != nil && cp.BlockId == next-1 {
+				start = cp.BlockId
+			}
+			return fetchBlocks(start, maxBlock, func(info *response.BlockDetailedInfo) error {
+				// a catch-up of many blocks stops at Ctrl-C after the block written
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				if cp != nil && info.Header.BlockId == cp.BlockId && next == cp.BlockId+1 {
+					if info.Hash == cp.Hash {
+						return nil
+					}
+					log.Warnf("block %d changed after a rollback, writing it again", cp.BlockId)
+					next = cp.BlockId
+				}
+				if info.Header.BlockId != next {
+					// a block missing from the page is requested again on the next poll
+					return fmt.Errorf("block %d missing, got block %d", next, info.Header.BlockId)
+				}
+				if err := emit(info); err != nil {
+					return err
+				}
+				cp = &chainCheckpoint{BlockId: info.Header.BlockId, Hash: info.Hash, Time: isoTime(info.Header.Time)}
+				next = cp.BlockId + 1
+				return writeCheckpoint(checkpointFile, cp)
+			})
+		}()
+		if ctx.Err() != nil {
+			return nil
+		}
+		wait := watchInterval
+		if err != nil {
+			log.Warnf("watch %s: %s, retrying in %s", name, err, backoff)
+			wait = backoff
+			backoff *= 2
+			if backoff > watchMaxBackoff {
+				backoff = watchMaxBackoff
+			}
+		} else {
+			backoff = watchInterval
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
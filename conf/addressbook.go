@@ -0,0 +1,89 @@
+package conf
+
+import (
+	"fmt"
+	"github.com/IBAX-io/ibax-cli/packages/consts"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// AliasPrefix marks an alias of the address book where an account address is accepted: @treasury
+const AliasPrefix = "@"
+
+// AddressEntry is an account of the address book
+type AddressEntry struct {
+	Address string `json:"address" yaml:"address"`
+	Note    string `json:"note" yaml:"note,omitempty"`
+}
+
+// AddressBook maps the aliases to the account addresses
+type AddressBook struct {
+	Addresses map[string]AddressEntry `yaml:"addresses"`
+}
+
+// AddressBookPath returns the address book file next to the config file
+func AddressBookPath() string {
+	return filepath.Join(filepath.Dir(Config.ConfigPath), consts.AddressBookFilename)
+}
+
+// CheckAliasName accepts names starting with a letter followed by letters, digits, '_', '-' or '.',
+// so that an alias isn't mistaken for a contract name like @1TokensSend or a param tag like @file:
+func CheckAliasName(name string) error {
+	if name == "" {
+		return fmt.Errorf("alias empty")
+	}
+	for i, r := range name {
+		letter := r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+		if letter || i > 0 && (r >= '0' && r <= '9' || r == '_' || r == '-' || r == '.') {
+			continue
+		}
+		return fmt.Errorf("alias [%s] invalid, it starts with a letter followed by letters, digits, '_', '-' or '.'", name)
+	}
+	return nil
+}
+
+// ReadAddressBook reads the address book, a missing file is an empty address book
+func ReadAddressBook(path string) (*AddressBook, error) {
+	book := &AddressBook{Addresses: make(map[string]AddressEntry)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return book, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = yaml.Unmarshal(data, book)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing address book %s", path)
+	}
+	if book.Addresses == nil {
+		book.Addresses = make(map[string]AddressEntry)
+	}
+	return book, nil
+}
+
+// WriteAddressBook saves the address book
+func WriteAddressBook(path string, book *AddressBook) error {
+	data, err := yaml.Marshal(book)
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of an existing file, like the config the book is only the user's
+	return os.Chmod(path, 0600)
+}
+
+// Aliases returns the aliases in alphabetical order
+func (b *AddressBook) Aliases() []string {
+	names := make([]string, 0, len(b.Addresses))
+	for name := range b.Addresses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
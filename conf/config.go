@@ -15,15 +15,21 @@ import (
 type GlobalConfig struct {
 	sdkConfig sdk.Config `yaml:"-"`
 
-	PrivateKey string `json:"private_key" yaml:"private_key"` // private key. Do not use clear text. You can set the environment variable. The key controls access to your funds!
-	Ecosystem  int64  `json:"ecosystem" yaml:"ecosystem"`     //Login ecosystem Id
-	Cryptoer   string `json:"cryptoer" yaml:"cryptoer"`
-	Hasher     string `json:"hasher" yaml:"hasher"`
+	PrivateKey     string `json:"private_key" yaml:"private_key"`         // private key. Do not use clear text. You can set the environment variable. The key controls access to your funds!
+	Keystore       string `json:"keystore" yaml:"keystore"`               // keystore file name in the keys directory, unlocked when private_key is empty
+	PassphraseFile string `json:"passphrase_file" yaml:"passphrase_file"` // file holding the keystore passphrase, prompted when empty and IBAX_PASSPHRASE is unset
+	Ecosystem      int64  `json:"ecosystem" yaml:"ecosystem"`             //Login ecosystem Id
+	Cryptoer       string `json:"cryptoer" yaml:"cryptoer"`
+	Hasher         string `json:"hasher" yaml:"hasher"`
+
+	Profile  string             `json:"profile" yaml:"profile,omitempty"`   // active profile, the top level settings are the "default" profile
+	Profiles map[string]Profile `json:"profiles" yaml:"profiles,omitempty"` // named node and key settings, see Profile
 
 	ConfigPath  string          `json:"config_path" yaml:"-"`
 	RpcConnect  string          `json:"rpc_connect" yaml:"rpc_connect"`
 	RpcPort     int             `json:"rpc_port" yaml:"rpc_port"`
 	LinerPath   string          `json:"liner_path" yaml:"liner_path"`
+	BlockCache  string          `json:"block_cache" yaml:"block_cache"` // block cache file, blocks.db in the data directory by default, "off" disables it
 	DirPathConf DirectoryConfig `json:"dir_path_conf" yaml:"dir_path_conf"`
 }
 
@@ -63,8 +69,24 @@ func FillRuntimePaths() error {
 	return nil
 }
 
+// ReadConfig reads the config file as written, environment variables are not expanded
+func ReadConfig(path string) (GlobalConfig, error) {
+	var c GlobalConfig
+	configData, err := os.ReadFile(path)
+	if err != nil {
+		return c, err
+	}
+	err = yaml.Unmarshal(configData, &c)
+	return c, err
+}
+
 // SaveConfig save global parameters to configFile
 func SaveConfig(path string) error {
+	return WriteConfig(path, Config)
+}
+
+// WriteConfig saves c to the config file
+func WriteConfig(path string, c GlobalConfig) error {
 	dir := filepath.Dir(path)
 	if _, err := os.Stat(dir); os.IsNotExist(err) {
 		err := os.Mkdir(dir, 0775)
@@ -80,7 +102,7 @@ func SaveConfig(path string) error {
 	}
 	defer cf.Close()
 
-	err = yaml.NewEncoder(cf).Encode(Config)
+	err = yaml.NewEncoder(cf).Encode(c)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,198 @@
+package conf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"strings"
+)
+
+// redacted replaces the secrets printed by Redact
+const redacted = "******"
+
+// secretKeys are the config keys holding secrets, at the top level and in the profiles
+var secretKeys = map[string]bool{"private_key": true}
+
+// Document is config.yml as yaml nodes, edits keep the comments and the order of the file.
+// Environment variables are not expanded, so that the file is saved as written
+type Document struct {
+	root yaml.Node
+}
+
+// ReadDocument reads the config file
+func ReadDocument(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	d := &Document{}
+	err = yaml.Unmarshal(data, &d.root)
+	if err != nil {
+		return nil, err
+	}
+	if d.root.Kind == 0 {
+		// empty file
+		d.root = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	if d.root.Kind != yaml.DocumentNode || len(d.root.Content) != 1 || d.root.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("config must be a yaml mapping")
+	}
+	return d, nil
+}
+
+// Config decodes the document, unknown keys and mismatched types are errors
+func (d *Document) Config() (GlobalConfig, error) {
+	var c GlobalConfig
+	data, err := yaml.Marshal(&d.root)
+	if err != nil {
+		return c, err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	err = dec.Decode(&c)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return c, err
+	}
+	return c, nil
+}
+
+// Get returns the value of a dotted key like dir_path_conf.keys_dir or profiles.testnet.rpc_port
+func (d *Document) Get(key string) (any, bool, error) {
+	node := d.lookup(key)
+	if node == nil {
+		return nil, false, nil
+	}
+	var v any
+	err := node.Decode(&v)
+	return v, true, err
+}
+
+// Set sets the scalar value of a dotted key, missing mappings are created.
+// The document must still decode to GlobalConfig afterwards
+func (d *Document) Set(key, value string) error {
+	node, err := d.entry(strings.Split(key, "."))
+	if err != nil {
+		return err
+	}
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("key [%s] is not a scalar", key)
+	}
+	// let the encoder resolve the tag, the schema check decides whether the value fits the field
+	node.Value, node.Tag, node.Style = value, "", 0
+	if _, err := d.Config(); err != nil {
+		return fmt.Errorf("set %s: %w", key, err)
+	}
+	return nil
+}
+
+// entry returns the node of the key parts, the missing mappings and a missing scalar are created
+func (d *Document) entry(parts []string) (*yaml.Node, error) {
+	node := d.root.Content[0]
+	for i, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("key [%s] invalid", strings.Join(parts, "."))
+		}
+		if node.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("key [%s] is not a mapping", strings.Join(parts[:i], "."))
+		}
+		child := mappingValue(node, part)
+		last := i == len(parts)-1
+		if child == nil {
+			child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			if last {
+				child = &yaml.Node{Kind: yaml.ScalarNode}
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: part}, child)
+		}
+		node = child
+	}
+	return node, nil
+}
+
+// remove deletes the key parts from the document, a missing key is ignored
+func (d *Document) remove(parts []string) {
+	node := d.root.Content[0]
+	for _, part := range parts[:len(parts)-1] {
+		if node = mappingValue(node, part); node == nil || node.Kind != yaml.MappingNode {
+			return
+		}
+	}
+	last := parts[len(parts)-1]
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == last {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// Save writes the document to path, readable only by the owner
+func (d *Document) Save(path string) error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(4)
+	if err := enc.Encode(&d.root); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of an existing file, the file may hold private keys
+	return os.Chmod(path, 0600)
+}
+
+// Map returns the document as a generic map
+func (d *Document) Map() (map[string]any, error) {
+	m := make(map[string]any)
+	err := d.root.Content[0].Decode(&m)
+	return m, err
+}
+
+func (d *Document) lookup(key string) *yaml.Node {
+	node := d.root.Content[0]
+	for _, part := range strings.Split(key, ".") {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		node = mappingValue(node, part)
+		if node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// IsSecret reports whether the last element of the dotted key names a secret
+func IsSecret(key string) bool {
+	parts := strings.Split(key, ".")
+	return secretKeys[parts[len(parts)-1]]
+}
+
+// Redact replaces the secrets in v, references to environment variables are kept as they reveal nothing
+func Redact(key string, v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			out[k] = Redact(k, item)
+		}
+		return out
+	case string:
+		if IsSecret(key) && val != "" && !strings.HasPrefix(val, "$") {
+			return redacted
+		}
+	}
+	return v
+}
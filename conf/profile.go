@@ -0,0 +1,126 @@
+package conf
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"sort"
+	"strings"
+)
+
+// DefaultProfile is the name of the top level node and key settings of config.yml
+const DefaultProfile = "default"
+
+// Profile is a named set of node and key settings, unset fields inherit the top level settings
+type Profile struct {
+	RpcConnect     string `json:"rpc_connect" yaml:"rpc_connect,omitempty"`
+	RpcPort        int    `json:"rpc_port" yaml:"rpc_port,omitempty"`
+	PrivateKey     string `json:"private_key" yaml:"private_key,omitempty"`
+	Keystore       string `json:"keystore" yaml:"keystore,omitempty"`
+	PassphraseFile string `json:"passphrase_file" yaml:"passphrase_file,omitempty"`
+	Ecosystem      int64  `json:"ecosystem" yaml:"ecosystem,omitempty"`
+	Cryptoer       string `json:"cryptoer" yaml:"cryptoer,omitempty"`
+	Hasher         string `json:"hasher" yaml:"hasher,omitempty"`
+}
+
+// CheckProfileName rejects names that can't be typed as a single console argument
+func CheckProfileName(name string) error {
+	if name == "" || strings.ContainsAny(name, " \t\n/\\") {
+		return fmt.Errorf("profile name [%s] invalid", name)
+	}
+	return nil
+}
+
+// ProfileNames returns the default profile followed by the named profiles in alphabetical order
+func (c *GlobalConfig) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append([]string{DefaultProfile}, names...)
+}
+
+// HasProfile reports whether name is the default profile or a named profile
+func (c *GlobalConfig) HasProfile(name string) bool {
+	if name == DefaultProfile {
+		return true
+	}
+	_, ok := c.Profiles[name]
+	return ok
+}
+
+// ApplyProfile overrides the top level settings with the profile name, or with the active profile of the file if name is empty.
+// The applied name is kept in Profile
+func (c *GlobalConfig) ApplyProfile(name string) error {
+	if name == "" {
+		name = c.Profile
+	}
+	if name == "" {
+		name = DefaultProfile
+	}
+	if !c.HasProfile(name) {
+		return fmt.Errorf("profile [%s] not found, use one of: %s", name, strings.Join(c.ProfileNames(), " | "))
+	}
+	c.Profile = name
+	if name == DefaultProfile {
+		return nil
+	}
+	p := c.Profiles[name]
+	if p.RpcConnect != "" {
+		c.RpcConnect = p.RpcConnect
+	}
+	if p.RpcPort != 0 {
+		c.RpcPort = p.RpcPort
+	}
+	// the key source of a profile replaces both the private key and the keystore of the top level
+	if p.PrivateKey != "" || p.Keystore != "" {
+		c.PrivateKey = p.PrivateKey
+		c.Keystore = p.Keystore
+	}
+	if p.PassphraseFile != "" {
+		c.PassphraseFile = p.PassphraseFile
+	}
+	if p.Ecosystem != 0 {
+		c.Ecosystem = p.Ecosystem
+	}
+	if p.Cryptoer != "" {
+		c.Cryptoer = p.Cryptoer
+	}
+	if p.Hasher != "" {
+		c.Hasher = p.Hasher
+	}
+	return nil
+}
+
+// SetProfile adds or replaces the profile name of the document, the comments of the file are kept
+func (d *Document) SetProfile(name string, p Profile) error {
+	var value yaml.Node
+	if err := value.Encode(p); err != nil {
+		return err
+	}
+	node, err := d.entry([]string{"profiles", name})
+	if err != nil {
+		return err
+	}
+	value.HeadComment, value.LineComment, value.FootComment = node.HeadComment, node.LineComment, node.FootComment
+	*node = value
+	_, err = d.Config()
+	return err
+}
+
+// DeleteProfile removes the profile name of the document, and the active profile setting when it names it
+func (d *Document) DeleteProfile(name string) {
+	d.remove([]string{"profiles", name})
+	if node := d.lookup("profile"); node != nil && node.Value == name {
+		d.remove([]string{"profile"})
+	}
+}
+
+// UseProfile sets the active profile of the document, the default profile removes the setting
+func (d *Document) UseProfile(name string) error {
+	if name == DefaultProfile || name == "" {
+		d.remove([]string{"profile"})
+		return nil
+	}
+	return d.Set("profile", name)
+}
@@ -1,44 +1,27 @@
 package models
 
 import (
-	"context"
-	"fmt"
-	log "github.com/sirupsen/logrus"
-	"github.com/spf13/cobra"
+	"github.com/IBAX-io/ibax-cli/packages/clierr"
 	"time"
 )
 
-func Login(cmd *cobra.Command) {
+func Login() error {
 	err := Client.AutoLogin()
 	if err != nil {
-		if IsConsoleMode() {
-			ctx := cmd.Context()
-			ctx = context.WithValue(ctx, "error", err)
-			cmd.SetContext(ctx)
-			SendErrSignal(fmt.Errorf("[login] Authorization failed:%s", err.Error()), false)
-			return
-		}
-		log.Fatalf("[login] Authorization failed: %s", err.Error())
-		return
+		return clierr.Wrap(clierr.Auth, err, "[login] Authorization failed")
 	}
+	return nil
 }
 
-func RefreshToken(cmd *cobra.Command) {
+func RefreshToken() error {
 	cfg := Client.GetConfig()
 	if time.Unix(cfg.TokenExpireTime, 0).Sub(time.Now()) < time.Minute*10 {
 		cfg.Token = ""
 		Client.SetConfig(cfg)
 		err := Client.AutoLogin()
 		if err != nil {
-			if IsConsoleMode() {
-				ctx := cmd.Context()
-				ctx = context.WithValue(ctx, "error", err)
-				cmd.SetContext(ctx)
-				SendErrSignal(fmt.Errorf("[refresh token] Authorization failed:%s", err.Error()), false)
-				return
-			}
-			log.Fatalf("[refresh token] Authorization failed: %s", err.Error())
-			return
+			return clierr.Wrap(clierr.Auth, err, "[refresh token] Authorization failed")
 		}
 	}
+	return nil
 }
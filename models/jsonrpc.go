@@ -0,0 +1,103 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/IBAX-io/go-ibax-sdk/packages/request"
+	"github.com/IBAX-io/go-ibax-sdk/packages/response"
+	"io"
+	"net/http"
+	"time"
+)
+
+// rpcTimeout limits a single JSON-RPC request
+const rpcTimeout = 30 * time.Second
+
+type rpcRequest struct {
+	JsonRPC string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+// CallRPC calls a JSON-RPC method of the node of the client, with the token of the client if logged in.
+// It is used for the node methods that the sdk client doesn't wrap
+func CallRPC(method string, result any, params ...any) error {
+	cfg := Client.GetConfig()
+	if params == nil {
+		params = []any{}
+	}
+	body, err := json.Marshal(rpcRequest{JsonRPC: "2.0", ID: 1, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, cfg.ApiAddress, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if cfg.Token != "" {
+		req.Header.Set("Authorization", cfg.JwtPrefix+cfg.Token)
+	}
+	resp, err := (&http.Client{Timeout: rpcTimeout}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var r rpcResponse
+	if err := json.Unmarshal(data, &r); err != nil {
+		return fmt.Errorf("%s: %s %s", method, resp.Status, bytes.TrimSpace(data))
+	}
+	if r.Error != nil {
+		return fmt.Errorf("%s: %s (code %d)", method, r.Error.Message, r.Error.Code)
+	}
+	if result == nil {
+		return nil
+	}
+	// the numbers of an untyped result keep all their digits, a key id doesn't fit in a float64
+	dec := json.NewDecoder(bytes.NewReader(r.Result))
+	dec.UseNumber()
+	return dec.Decode(result)
+}
+
+// DetailedBlocks returns the blocks blockId..blockId+count-1 like the sdk client,
+// the numbers of the transaction params are json.Number
+func DetailedBlocks(blockId, count int64) (*map[int64]response.BlockDetailedInfo, error) {
+	var result map[int64]response.BlockDetailedInfo
+	if err := CallRPC("ibax.detailedBlocks", &result, blockId, count); err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, nil
+	}
+	return &result, nil
+}
+
+// DetailedBlock returns the block of the id or hash like the sdk client,
+// the numbers of the transaction params are json.Number
+func DetailedBlock(bh request.BlockIdOrHash) (*response.BlockDetailedInfo, error) {
+	param := map[string]any{"id": bh.Id}
+	if bh.Hash != "" {
+		param = map[string]any{"hash": bh.Hash}
+	}
+	var result *response.BlockDetailedInfo
+	if err := CallRPC("ibax.detailedBlock", &result, param); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
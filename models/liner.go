@@ -21,7 +21,15 @@ var (
 
 	consoleMode bool
 
+	// activeConsole is the line editor of the running console
+	activeConsole *linerConsole
+
 	ErrSignal = make(chan ErrInfo, 3)
+
+	// PrintError prints the error returned by a command executed in the console
+	PrintError = func(err error) {
+		fmt.Println(err.Error())
+	}
 )
 
 type ErrInfo struct {
@@ -87,20 +95,15 @@ func MakeDirectory(dir string) error {
 }
 
 func NewTerminalLiner(line *linerConsole) {
+	activeConsole = line
+	defer func() {
+		activeConsole = nil
+	}()
 	line.SetCtrlCAborts(true)
 	line.SetMultiLineMode(true)
 	line.SetTabCompletionStyle(liner.TabPrints)
 
-	line.SetWordCompleter(func(line string, pos int) (head string, completions []string, tail string) {
-		keyWorld := strings.ToLower(line)
-		for _, n := range wordCompletions {
-			word := strings.ToLower(n)
-			if strings.HasPrefix(word, keyWorld) {
-				completions = append(completions, n)
-			}
-		}
-		return
-	})
+	line.SetWordCompleter(consoleCompleter)
 
 	//line.SetCompleter(func(line string) (c []string) {
 	//	for _, n := range wordCompletions {
@@ -169,7 +172,7 @@ func NewTerminalLiner(line *linerConsole) {
 				resetAllFlags(subCmd)
 				err = subCmd.Execute()
 				if err != nil {
-					fmt.Println(err.Error())
+					PrintError(err)
 					continue
 				}
 			}
@@ -187,6 +190,18 @@ func NewTerminalLiner(line *linerConsole) {
 
 }
 
+// consoleCompleter completes the command names
+func consoleCompleter(line string, pos int) (head string, completions []string, tail string) {
+	keyWorld := strings.ToLower(line)
+	for _, n := range wordCompletions {
+		word := strings.ToLower(n)
+		if strings.HasPrefix(word, keyWorld) {
+			completions = append(completions, n)
+		}
+	}
+	return
+}
+
 // countIndents returns the number of indentations for the given input.
 // In case of invalid input such as var a = } the result can be negative.
 func countIndents(input string) int {
@@ -239,10 +254,15 @@ func resetAllFlags(cmd *cobra.Command) {
 				switch f.Value.Type() {
 				case "bool":
 					f.Value.Set(f.DefValue)
-				case "int", "int64":
+				case "int", "int64", "float64", "duration":
 					f.Value.Set(f.DefValue)
 				case "string":
 					f.Value.Set(f.DefValue)
+				case "stringArray", "stringSlice":
+					// Set appends to the values of the previous command
+					if v, ok := f.Value.(pflag.SliceValue); ok {
+						v.Replace(nil)
+					}
 				}
 			}
 		})
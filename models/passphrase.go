@@ -0,0 +1,94 @@
+package models
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/IBAX-io/ibax-cli/conf"
+	"github.com/IBAX-io/ibax-cli/packages/consts"
+	"github.com/peterh/liner"
+	"os"
+	"strings"
+)
+
+// ReadPassphrase returns the keystore passphrase from the IBAX_PASSPHRASE environment variable
+// or the configured passphrase file, otherwise it's prompted without echo
+func ReadPassphrase(prompt string) (string, error) {
+	if passphrase, ok := os.LookupEnv(consts.PassphraseEnv); ok {
+		return passphrase, nil
+	}
+	if conf.Config.PassphraseFile != "" {
+		data, err := os.ReadFile(conf.Config.PassphraseFile)
+		if err != nil {
+			return "", fmt.Errorf("read passphrase file: %w", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+	return PasswordPrompt(prompt)
+}
+
+// NewPassphrase reads a new passphrase, a prompted passphrase has to be repeated
+func NewPassphrase(prompt string) (string, error) {
+	if _, ok := os.LookupEnv(consts.PassphraseEnv); ok || conf.Config.PassphraseFile != "" {
+		return ReadPassphrase(prompt)
+	}
+	return promptNewPassphrase(prompt)
+}
+
+// ChangedPassphrase reads the new passphrase of a keystore whose old one is read by ReadPassphrase,
+// from the IBAX_NEW_PASSPHRASE environment variable or the file, otherwise it's prompted and repeated
+func ChangedPassphrase(filename, prompt string) (string, error) {
+	passphrase, ok := os.LookupEnv(consts.NewPassphraseEnv)
+	if !ok && filename != "" {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return "", fmt.Errorf("read new passphrase file: %w", err)
+		}
+		passphrase, ok = strings.TrimRight(string(data), "\r\n"), true
+	}
+	if !ok {
+		return promptNewPassphrase(prompt)
+	}
+	if passphrase == "" {
+		return "", errors.New("passphrase can't be empty")
+	}
+	return passphrase, nil
+}
+
+func promptNewPassphrase(prompt string) (string, error) {
+	passphrase, err := PasswordPrompt(prompt)
+	if err != nil {
+		return "", err
+	}
+	repeat, err := PasswordPrompt("Repeat passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if passphrase != repeat {
+		return "", errors.New("passphrases do not match")
+	}
+	if passphrase == "" {
+		return "", errors.New("passphrase can't be empty")
+	}
+	return passphrase, nil
+}
+
+// PasswordPrompt prompts without echo, the console line editor is used when the console is running.
+// If stdin isn't a terminal, a line is read from it.
+func PasswordPrompt(prompt string) (string, error) {
+	if activeConsole != nil {
+		return activeConsole.PasswordPrompt(prompt)
+	}
+	line := liner.NewLiner()
+	passphrase, err := line.PasswordPrompt(prompt)
+	line.Close()
+	if errors.Is(err, liner.ErrNotTerminalOutput) {
+		fmt.Fprint(os.Stderr, prompt)
+		passphrase, err = bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && passphrase == "" {
+			return "", err
+		}
+		return strings.TrimRight(passphrase, "\r\n"), nil
+	}
+	return passphrase, err
+}
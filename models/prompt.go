@@ -0,0 +1,90 @@
+package models
+
+import (
+	"errors"
+	"github.com/peterh/liner"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrPromptCancelled is returned when a prompt is aborted with ctrl-c or ctrl-d
+var ErrPromptCancelled = errors.New("cancelled")
+
+// Prompter reads the answers of an interactive command.
+// The console line editor is used when the console is running, so the answers share its history
+type Prompter struct {
+	state *liner.State
+	owned bool
+}
+
+func NewPrompter() *Prompter {
+	if activeConsole != nil {
+		return &Prompter{state: activeConsole.State}
+	}
+	state := liner.NewLiner()
+	state.SetCtrlCAborts(true)
+	return &Prompter{state: state, owned: true}
+}
+
+func (p *Prompter) Close() {
+	if p.owned {
+		p.state.Close()
+	}
+}
+
+// Prompt reads a line with echo
+func (p *Prompter) Prompt(prompt string) (string, error) {
+	return p.prompt(prompt, nil)
+}
+
+// PromptPath reads a file path, tab completes the file names
+func (p *Prompter) PromptPath(prompt string) (string, error) {
+	return p.prompt(prompt, pathCompleter)
+}
+
+// AppendHistory adds the command to the console history, saved when the console exits
+func (p *Prompter) AppendHistory(command string) {
+	if !p.owned {
+		p.state.AppendHistory(command)
+	}
+}
+
+func (p *Prompter) prompt(prompt string, completer liner.WordCompleter) (string, error) {
+	if completer != nil {
+		p.state.SetWordCompleter(completer)
+		if !p.owned {
+			defer p.state.SetWordCompleter(consoleCompleter)
+		}
+	}
+	text, err := p.state.Prompt(prompt)
+	if errors.Is(err, liner.ErrPromptAborted) || (err != nil && err.Error() == "EOF") {
+		return "", ErrPromptCancelled
+	}
+	return strings.TrimSpace(text), err
+}
+
+// pathCompleter completes the file name before the cursor, directories end with the path separator
+func pathCompleter(line string, pos int) (head string, completions []string, tail string) {
+	input := line[:pos]
+	dir, base := filepath.Split(input)
+	readDir := dir
+	if readDir == "" {
+		readDir = "."
+	}
+	entries, err := os.ReadDir(readDir)
+	if err != nil {
+		return "", nil, line[pos:]
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, base) || (base == "" && strings.HasPrefix(name, ".")) {
+			continue
+		}
+		if entry.IsDir() {
+			name += string(filepath.Separator)
+		}
+		completions = append(completions, dir+name)
+	}
+	return "", completions, line[pos:]
+}
@@ -0,0 +1,339 @@
+// Package blockcache keeps the blocks fetched from the node in a bbolt file, so that old blocks,
+// which don't change, are read from the disk instead of the node.
+//
+// Buckets:
+//
+//	blocks		block id (8 bytes big endian) -> json of the detailed block with its transactions
+//	hashes		block hash -> block id
+//	infos		block id -> json of the block info of getBlockInfo
+//	meta		network_id -> network id of the node the blocks are from
+//
+// A block is stored with its hash and read only when the hash index still points to it, a block of another
+// hash at the same id replaces it together with the block info of the old hash.
+package blockcache
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/IBAX-io/go-ibax-sdk/packages/response"
+	"go.etcd.io/bbolt"
+	"os"
+	"time"
+)
+
+var (
+	bucketBlocks = []byte("blocks")
+	bucketHashes = []byte("hashes")
+	bucketInfos  = []byte("infos")
+	bucketMeta   = []byte("meta")
+	keyNetwork   = []byte("network_id")
+
+	// ErrLocked is returned by Open when another process uses the cache
+	ErrLocked = errors.New("block cache is used by another process")
+)
+
+// Cache is the block cache file
+type Cache struct {
+	db *bbolt.DB
+}
+
+// Stats are the counts of the cache
+type Stats struct {
+	Path         string `json:"path"`
+	NetworkId    string `json:"network_id"`
+	Blocks       int    `json:"blocks"`
+	Transactions int    `json:"transactions"`
+	Infos        int    `json:"infos"`
+	FirstBlock   int64  `json:"first_block"`
+	LastBlock    int64  `json:"last_block"`
+	Size         int64  `json:"size"`
+}
+
+// Open opens or creates the cache file, waiting up to timeout for another process to close it
+func Open(path string, timeout time.Duration) (*Cache, error) {
+	db, err := bbolt.Open(path, 0644, &bbolt.Options{Timeout: timeout})
+	if errors.Is(err, bbolt.ErrTimeout) {
+		return nil, ErrLocked
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range [][]byte{bucketBlocks, bucketHashes, bucketInfos, bucketMeta} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Cache{db: db}, nil
+}
+
+// Close closes the cache file
+func (c *Cache) Close() error {
+	return c.db.Close()
+}
+
+// Path returns the cache file
+func (c *Cache) Path() string {
+	return c.db.Path()
+}
+
+// SetNetwork binds the cache to the network of the node, the blocks of another network are removed.
+// It reports whether the cache was cleared.
+func (c *Cache) SetNetwork(networkId string) (cleared bool, err error) {
+	err = c.db.Update(func(tx *bbolt.Tx) error {
+		meta := tx.Bucket(bucketMeta)
+		current := meta.Get(keyNetwork)
+		if string(current) == networkId {
+			return nil
+		}
+		if current != nil {
+			cleared = true
+			for _, name := range [][]byte{bucketBlocks, bucketHashes, bucketInfos} {
+				if err := tx.DeleteBucket(name); err != nil {
+					return err
+				}
+				if _, err := tx.CreateBucket(name); err != nil {
+					return err
+				}
+			}
+		}
+		return meta.Put(keyNetwork, []byte(networkId))
+	})
+	return cleared, err
+}
+
+func idKey(id int64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(id))
+	return key
+}
+
+func keyId(key []byte) int64 {
+	return int64(binary.BigEndian.Uint64(key))
+}
+
+// block reads the block of the id, it is nil when it is missing or its hash isn't indexed to the id
+func block(tx *bbolt.Tx, id int64) (*response.BlockDetailedInfo, error) {
+	data := tx.Bucket(bucketBlocks).Get(idKey(id))
+	if data == nil {
+		return nil, nil
+	}
+	info := &response.BlockDetailedInfo{}
+	// the numbers of the transaction params are json.Number, a key id doesn't fit in a float64
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(info); err != nil {
+		return nil, fmt.Errorf("block %d of the cache: %w", id, err)
+	}
+	indexed := tx.Bucket(bucketHashes).Get([]byte(info.Hash))
+	if info.Header.BlockId != id || indexed == nil || keyId(indexed) != id {
+		return nil, nil
+	}
+	return info, nil
+}
+
+// Block returns the block of the id, nil when it isn't cached
+func (c *Cache) Block(id int64) (info *response.BlockDetailedInfo, err error) {
+	err = c.db.View(func(tx *bbolt.Tx) error {
+		info, err = block(tx, id)
+		return err
+	})
+	return
+}
+
+// BlockByHash returns the block of the hash, nil when it isn't cached
+func (c *Cache) BlockByHash(hash string) (info *response.BlockDetailedInfo, err error) {
+	err = c.db.View(func(tx *bbolt.Tx) error {
+		id := tx.Bucket(bucketHashes).Get([]byte(hash))
+		if id == nil {
+			return nil
+		}
+		info, err = block(tx, keyId(id))
+		return err
+	})
+	return
+}
+
+// remove deletes the block and the block info of the id
+func remove(tx *bbolt.Tx, id int64) error {
+	key := idKey(id)
+	blocks := tx.Bucket(bucketBlocks)
+	if data := blocks.Get(key); data != nil {
+		var old response.BlockDetailedInfo
+		if json.Unmarshal(data, &old) == nil {
+			hashes := tx.Bucket(bucketHashes)
+			if indexed := hashes.Get([]byte(old.Hash)); indexed != nil && keyId(indexed) == id {
+				if err := hashes.Delete([]byte(old.Hash)); err != nil {
+					return err
+				}
+			}
+		}
+		if err := blocks.Delete(key); err != nil {
+			return err
+		}
+	}
+	return tx.Bucket(bucketInfos).Delete(key)
+}
+
+// PutBlocks stores the blocks, a cached block of another hash at the same id is replaced
+func (c *Cache) PutBlocks(list ...*response.BlockDetailedInfo) error {
+	if len(list) == 0 {
+		return nil
+	}
+	return c.db.Update(func(tx *bbolt.Tx) error {
+		for _, info := range list {
+			id := info.Header.BlockId
+			if id <= 0 || info.Hash == "" {
+				return fmt.Errorf("block %d without id or hash", id)
+			}
+			old, err := block(tx, id)
+			if err != nil {
+				return err
+			}
+			if old != nil && old.Hash == info.Hash {
+				continue
+			}
+			if err = remove(tx, id); err != nil {
+				return err
+			}
+			data, err := json.Marshal(info)
+			if err != nil {
+				return err
+			}
+			if err = tx.Bucket(bucketBlocks).Put(idKey(id), data); err != nil {
+				return err
+			}
+			if err = tx.Bucket(bucketHashes).Put([]byte(info.Hash), idKey(id)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Info returns the block info of the id, nil when it isn't cached or the cached block has another hash
+func (c *Cache) Info(id int64) (result *response.BlockInfoResult, err error) {
+	err = c.db.View(func(tx *bbolt.Tx) error {
+		data := tx.Bucket(bucketInfos).Get(idKey(id))
+		if data == nil {
+			return nil
+		}
+		info := &response.BlockInfoResult{}
+		if err := json.Unmarshal(data, info); err != nil {
+			return fmt.Errorf("block info %d of the cache: %w", id, err)
+		}
+		b, err := block(tx, id)
+		if err != nil || b != nil && b.Hash != info.Hash {
+			return err
+		}
+		result = info
+		return nil
+	})
+	return
+}
+
+// PutInfo stores the block info of the id, a cached block of another hash is removed
+func (c *Cache) PutInfo(id int64, info *response.BlockInfoResult) error {
+	if id <= 0 || info.Hash == "" {
+		return fmt.Errorf("block info %d without id or hash", id)
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	return c.db.Update(func(tx *bbolt.Tx) error {
+		b, err := block(tx, id)
+		if err != nil {
+			return err
+		}
+		if b != nil && b.Hash != info.Hash {
+			if err = remove(tx, id); err != nil {
+				return err
+			}
+		}
+		return tx.Bucket(bucketInfos).Put(idKey(id), data)
+	})
+}
+
+// DeleteInfos removes the block infos of the ids, the blocks are kept
+func (c *Cache) DeleteInfos(ids ...int64) error {
+	return c.db.Update(func(tx *bbolt.Tx) error {
+		for _, id := range ids {
+			if err := tx.Bucket(bucketInfos).Delete(idKey(id)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Ids returns the ids of the cached blocks and block infos in order
+func (c *Cache) Ids() (blocks, infos []int64, err error) {
+	err = c.db.View(func(tx *bbolt.Tx) error {
+		err := tx.Bucket(bucketBlocks).ForEach(func(k, v []byte) error {
+			blocks = append(blocks, keyId(k))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(bucketInfos).ForEach(func(k, v []byte) error {
+			infos = append(infos, keyId(k))
+			return nil
+		})
+	})
+	return
+}
+
+// Delete removes the blocks and the block infos of the ids
+func (c *Cache) Delete(ids ...int64) error {
+	return c.db.Update(func(tx *bbolt.Tx) error {
+		for _, id := range ids {
+			if err := remove(tx, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Stats counts the cached blocks
+func (c *Cache) Stats() (*Stats, error) {
+	s := &Stats{Path: c.Path()}
+	err := c.db.View(func(tx *bbolt.Tx) error {
+		s.NetworkId = string(tx.Bucket(bucketMeta).Get(keyNetwork))
+		s.Infos = tx.Bucket(bucketInfos).Stats().KeyN
+		return tx.Bucket(bucketBlocks).ForEach(func(k, v []byte) error {
+			var info struct {
+				Transactions []json.RawMessage `json:"transactions"`
+			}
+			if err := json.Unmarshal(v, &info); err != nil {
+				return fmt.Errorf("block %d of the cache: %w", keyId(k), err)
+			}
+			id := keyId(k)
+			if s.Blocks == 0 {
+				s.FirstBlock = id
+			}
+			s.LastBlock = id
+			s.Blocks++
+			s.Transactions += len(info.Transactions)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if fi, err := os.Stat(c.Path()); err == nil {
+		s.Size = fi.Size()
+	}
+	return s, nil
+}
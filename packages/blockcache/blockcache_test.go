@@ -0,0 +1,179 @@
+package blockcache
+
+import (
+	"encoding/json"
+	"github.com/IBAX-io/go-ibax-sdk/packages/response"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func openTemp(t *testing.T) *Cache {
+	t.Helper()
+	c, err := Open(filepath.Join(t.TempDir(), "blocks.db"), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func testBlock(id int64, hash string, txs ...string) *response.BlockDetailedInfo {
+	info := &response.BlockDetailedInfo{Header: response.BlockHeaderInfo{BlockId: id}, Hash: hash}
+	for _, tx := range txs {
+		info.Transactions = append(info.Transactions, response.TxInfo{Hash: tx})
+	}
+	return info
+}
+
+func TestPutBlocks(t *testing.T) {
+	c := openTemp(t)
+	if err := c.PutBlocks(testBlock(1, "a1", "t1"), testBlock(2, "a2")); err != nil {
+		t.Fatal(err)
+	}
+	info, err := c.Block(1)
+	if err != nil || info == nil || info.Hash != "a1" || len(info.Transactions) != 1 {
+		t.Fatalf("Block(1) = %+v, %v, want the block a1 with 1 transaction", info, err)
+	}
+	if info, err = c.BlockByHash("a2"); err != nil || info == nil || info.Header.BlockId != 2 {
+		t.Fatalf("BlockByHash(a2) = %+v, %v, want the block 2", info, err)
+	}
+	if info, err = c.Block(4); err != nil || info != nil {
+		t.Fatalf("Block(4) = %+v, %v, want nil", info, err)
+	}
+	if err = c.PutBlocks(testBlock(0, "a0")); err == nil {
+		t.Error("PutBlocks of a block without id, want an error")
+	}
+	if err = c.PutBlocks(testBlock(4, "")); err == nil {
+		t.Error("PutBlocks of a block without hash, want an error")
+	}
+	// a key id of the params keeps all its digits
+	utxo := testBlock(3, "a3", "t3")
+	utxo.Transactions[0].Params = map[string]any{"UTXO": map[string]any{"ToID": json.Number("4611686018427387905")}}
+	if err = c.PutBlocks(utxo); err != nil {
+		t.Fatal(err)
+	}
+	if info, err = c.Block(3); err != nil || info == nil {
+		t.Fatalf("Block(3) = %+v, %v, want the block a3", info, err)
+	}
+	if to := info.Transactions[0].Params["UTXO"].(map[string]any)["ToID"]; to != json.Number("4611686018427387905") {
+		t.Errorf("ToID = %#v, want json.Number 4611686018427387905", to)
+	}
+	if err = c.Delete(3); err != nil {
+		t.Fatal(err)
+	}
+	stats, err := c.Stats()
+	if err != nil || stats.Blocks != 2 || stats.Transactions != 1 || stats.FirstBlock != 1 || stats.LastBlock != 2 {
+		t.Errorf("Stats() = %+v, %v, want 2 blocks 1..2 with 1 transaction", stats, err)
+	}
+}
+
+func TestPutBlocksReplace(t *testing.T) {
+	c := openTemp(t)
+	if err := c.PutBlocks(testBlock(5, "old", "t1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PutInfo(5, &response.BlockInfoResult{Hash: "old"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PutBlocks(testBlock(5, "new")); err != nil {
+		t.Fatal(err)
+	}
+	info, err := c.Block(5)
+	if err != nil || info == nil || info.Hash != "new" || len(info.Transactions) != 0 {
+		t.Fatalf("Block(5) = %+v, %v, want the block new", info, err)
+	}
+	if info, err = c.BlockByHash("old"); err != nil || info != nil {
+		t.Errorf("BlockByHash(old) = %+v, %v, want nil", info, err)
+	}
+	if result, err := c.Info(5); err != nil || result != nil {
+		t.Errorf("Info(5) = %+v, %v, want nil after the block is replaced", result, err)
+	}
+}
+
+func TestInfo(t *testing.T) {
+	c := openTemp(t)
+	if err := c.PutBlocks(testBlock(7, "b7")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PutInfo(7, &response.BlockInfoResult{Hash: "b7", Tx: 3}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := c.Info(7)
+	if err != nil || result == nil || result.Tx != 3 {
+		t.Fatalf("Info(7) = %+v, %v, want the info of b7", result, err)
+	}
+
+	// an info of another hash removes the cached block
+	if err = c.PutInfo(7, &response.BlockInfoResult{Hash: "c7"}); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := c.Block(7); err != nil || info != nil {
+		t.Errorf("Block(7) = %+v, %v, want nil after an info of another hash", info, err)
+	}
+	if result, err = c.Info(7); err != nil || result == nil || result.Hash != "c7" {
+		t.Errorf("Info(7) = %+v, %v, want the info of c7", result, err)
+	}
+
+	if err = c.DeleteInfos(7); err != nil {
+		t.Fatal(err)
+	}
+	if result, err = c.Info(7); err != nil || result != nil {
+		t.Errorf("Info(7) = %+v, %v, want nil after DeleteInfos", result, err)
+	}
+	if err = c.PutInfo(8, &response.BlockInfoResult{}); err == nil {
+		t.Error("PutInfo without hash, want an error")
+	}
+}
+
+func TestSetNetwork(t *testing.T) {
+	c := openTemp(t)
+	cleared, err := c.SetNetwork("1")
+	if err != nil || cleared {
+		t.Fatalf("SetNetwork(1) of a new cache = %v, %v, want false", cleared, err)
+	}
+	if err = c.PutBlocks(testBlock(1, "a1")); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.PutInfo(1, &response.BlockInfoResult{Hash: "a1"}); err != nil {
+		t.Fatal(err)
+	}
+	if cleared, err = c.SetNetwork("1"); err != nil || cleared {
+		t.Fatalf("SetNetwork(1) again = %v, %v, want false", cleared, err)
+	}
+	if info, err := c.Block(1); err != nil || info == nil {
+		t.Fatalf("Block(1) = %+v, %v, want the block kept on the same network", info, err)
+	}
+
+	if cleared, err = c.SetNetwork("2"); err != nil || !cleared {
+		t.Fatalf("SetNetwork(2) = %v, %v, want true", cleared, err)
+	}
+	blocks, infos, err := c.Ids()
+	if err != nil || len(blocks) != 0 || len(infos) != 0 {
+		t.Errorf("Ids() = %v, %v, %v, want none after another network", blocks, infos, err)
+	}
+	if info, err := c.BlockByHash("a1"); err != nil || info != nil {
+		t.Errorf("BlockByHash(a1) = %+v, %v, want nil after another network", info, err)
+	}
+	stats, err := c.Stats()
+	if err != nil || stats.NetworkId != "2" {
+		t.Errorf("Stats() = %+v, %v, want network 2", stats, err)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	c := openTemp(t)
+	if err := c.PutBlocks(testBlock(1, "a1"), testBlock(2, "a2"), testBlock(3, "a3")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Delete(1, 3); err != nil {
+		t.Fatal(err)
+	}
+	blocks, _, err := c.Ids()
+	if err != nil || len(blocks) != 1 || blocks[0] != 2 {
+		t.Errorf("Ids() = %v, %v, want [2]", blocks, err)
+	}
+	if info, err := c.BlockByHash("a1"); err != nil || info != nil {
+		t.Errorf("BlockByHash(a1) = %+v, %v, want nil after Delete", info, err)
+	}
+}
@@ -0,0 +1,246 @@
+// Package centrifugo is a client of the json protocol of Centrifugo v2, the server the nodes publish
+// notifications to. The node publishes the notifications of an account to the channel "client" + account address.
+//
+// A frame holds commands or replies separated by new lines:
+//
+//	-> {"id":1,"params":{"token":"jwt"}}						connect
+//	<- {"id":1,"result":{"client":"uid","version":"2.8.0"}}
+//	-> {"id":2,"method":1,"params":{"channel":"client0666-..."}}	subscribe
+//	<- {"id":2,"result":{}}
+//	<- {"result":{"channel":"client0666-...","data":{"data":[...]}}}	publication, a reply without id
+//	<- {"id":3,"error":{"code":109,"message":"token expired"}}
+package centrifugo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang.org/x/net/websocket"
+	"net"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	methodConnect   = 0
+	methodSubscribe = 1
+	methodPing      = 7
+
+	pushPublication = 0
+	pushUnsub       = 3
+
+	// WebsocketPath is the websocket endpoint of the server, added when the url has no path
+	WebsocketPath = "/connection/websocket"
+
+	// ErrTokenExpired is the code of the error replied to an expired token
+	ErrTokenExpired = 109
+)
+
+// Error is an error replied by the server
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("centrifugo error %d: %s", e.Code, e.Message)
+}
+
+// Publication is the data published to a channel
+type Publication struct {
+	Channel string
+	Data    json.RawMessage
+}
+
+type command struct {
+	Id     uint32 `json:"id"`
+	Method int    `json:"method,omitempty"`
+	Params any    `json:"params,omitempty"`
+}
+
+type reply struct {
+	Id     uint32          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *Error          `json:"error"`
+}
+
+type push struct {
+	Type    int             `json:"type"`
+	Channel string          `json:"channel"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// Client is a connection to the server, Ping may be called while Read waits
+type Client struct {
+	conn    *websocket.Conn
+	lastId  uint32
+	pending []Publication
+}
+
+// WebsocketURL returns the websocket url of the server url given by the node, http urls are changed to ws
+func WebsocketURL(server string) (string, error) {
+	u, err := url.Parse(server)
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "http":
+		u.Scheme = "ws"
+	case "https":
+		u.Scheme = "wss"
+	case "ws", "wss":
+	default:
+		return "", fmt.Errorf("url [%s] invalid, ws, wss, http or https expected", server)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = WebsocketPath
+	}
+	return u.String(), nil
+}
+
+// Dial connects to the websocket url and authenticates with the token
+func Dial(wsURL, token string, timeout time.Duration) (*Client, error) {
+	origin := strings.Replace(wsURL, "ws", "http", 1)
+	cfg, err := websocket.NewConfig(wsURL, origin)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Dialer = &net.Dialer{Timeout: timeout}
+	conn, err := websocket.DialConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{conn: conn}
+	if _, err = c.call(methodConnect, map[string]string{"token": token}, timeout); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Subscribe subscribes to the channel
+func (c *Client) Subscribe(channel string, timeout time.Duration) error {
+	_, err := c.call(methodSubscribe, map[string]string{"channel": channel}, timeout)
+	if err != nil {
+		return fmt.Errorf("subscribe %s: %w", channel, err)
+	}
+	return nil
+}
+
+// Ping keeps the connection alive, its reply is skipped by Read
+func (c *Client) Ping() error {
+	return c.send(command{Id: atomic.AddUint32(&c.lastId, 1), Method: methodPing})
+}
+
+// Read waits for the next publication, the deadline is reset by every frame
+func (c *Client) Read(timeout time.Duration) (*Publication, error) {
+	for len(c.pending) == 0 {
+		replies, err := c.receive(timeout)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range replies {
+			if r.Id != 0 {
+				if r.Error != nil {
+					return nil, r.Error
+				}
+				continue
+			}
+			if err = c.push(r); err != nil {
+				return nil, err
+			}
+		}
+	}
+	pub := c.pending[0]
+	c.pending = c.pending[1:]
+	return &pub, nil
+}
+
+// Close closes the connection, a blocked Read returns an error
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) send(cmd command) error {
+	data, err := json.Marshal(cmd)
+	if err != nil {
+		return err
+	}
+	return websocket.Message.Send(c.conn, string(data))
+}
+
+// call sends the command and waits for its reply, the publications received meanwhile are kept for Read
+func (c *Client) call(method int, params any, timeout time.Duration) (json.RawMessage, error) {
+	id := atomic.AddUint32(&c.lastId, 1)
+	if err := c.send(command{Id: id, Method: method, Params: params}); err != nil {
+		return nil, err
+	}
+	for {
+		replies, err := c.receive(timeout)
+		if err != nil {
+			return nil, err
+		}
+		var result *reply
+		for i, r := range replies {
+			switch {
+			case r.Id == id:
+				result = &replies[i]
+			case r.Id == 0:
+				if err = c.push(r); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if result != nil && result.Error != nil {
+			return nil, result.Error
+		}
+		if result != nil {
+			return result.Result, nil
+		}
+	}
+}
+
+func (c *Client) receive(timeout time.Duration) ([]reply, error) {
+	if err := c.conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+	var frame []byte
+	if err := websocket.Message.Receive(c.conn, &frame); err != nil {
+		return nil, err
+	}
+	var replies []reply
+	for _, line := range bytes.Split(frame, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var r reply
+		if err := json.Unmarshal(line, &r); err != nil {
+			return nil, fmt.Errorf("reply [%s] invalid: %w", line, err)
+		}
+		replies = append(replies, r)
+	}
+	return replies, nil
+}
+
+func (c *Client) push(r reply) error {
+	var p push
+	if err := json.Unmarshal(r.Result, &p); err != nil {
+		return fmt.Errorf("push [%s] invalid: %w", r.Result, err)
+	}
+	switch p.Type {
+	case pushPublication:
+		var pub struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(p.Data, &pub); err != nil {
+			return fmt.Errorf("publication [%s] invalid: %w", p.Data, err)
+		}
+		c.pending = append(c.pending, Publication{Channel: p.Channel, Data: pub.Data})
+	case pushUnsub:
+		return errors.New("unsubscribed from " + p.Channel + " by the server")
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package centrifugo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang.org/x/net/websocket"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// standIn is a local server answering the connect and subscribe commands like Centrifugo v2,
+// it publishes the frames of publish to the channel after the subscription
+func standIn(t *testing.T, token string, publish ...string) *httptest.Server {
+	return httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		for {
+			var frame string
+			if err := websocket.Message.Receive(ws, &frame); err != nil {
+				return
+			}
+			var cmd struct {
+				Id     uint32            `json:"id"`
+				Method int               `json:"method"`
+				Params map[string]string `json:"params"`
+			}
+			if err := json.Unmarshal([]byte(frame), &cmd); err != nil {
+				t.Errorf("command %s: %s", frame, err)
+				return
+			}
+			switch cmd.Method {
+			case methodConnect:
+				if cmd.Params["token"] != token {
+					websocket.Message.Send(ws, fmt.Sprintf(`{"id":%d,"error":{"code":109,"message":"token expired"}}`, cmd.Id))
+					continue
+				}
+				websocket.Message.Send(ws, fmt.Sprintf(`{"id":%d,"result":{"client":"c1","version":"2.8.0"}}`, cmd.Id))
+			case methodSubscribe:
+				// the reply and a publication in one frame
+				websocket.Message.Send(ws, fmt.Sprintf(`{"id":%d,"result":{}}`, cmd.Id)+"\n"+
+					`{"result":{"channel":"`+cmd.Params["channel"]+`","data":{"data":"first","offset":1}}}`)
+				for _, p := range publish {
+					websocket.Message.Send(ws, p)
+				}
+			case methodPing:
+				websocket.Message.Send(ws, fmt.Sprintf(`{"id":%d}`, cmd.Id))
+			}
+		}
+	}))
+}
+
+func TestClient(t *testing.T) {
+	srv := standIn(t, "jwt",
+		`{"result":{"type":1,"channel":"client1","data":{"info":{}}}}`,
+		`{"result":{"channel":"client1","data":{"data":[{"ecosystem":"1","role_id":"2","count":3}]}}}`,
+	)
+	defer srv.Close()
+	wsURL, err := WebsocketURL(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(wsURL, WebsocketPath) || !strings.HasPrefix(wsURL, "ws://") {
+		t.Fatalf("url %s", wsURL)
+	}
+	client, err := Dial(wsURL, "jwt", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	if err = client.Subscribe("client1", time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err = client.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{`"first"`, `[{"ecosystem":"1","role_id":"2","count":3}]`}
+	for _, w := range want {
+		pub, err := client.Read(time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pub.Channel != "client1" || string(pub.Data) != w {
+			t.Errorf("publication %s %s, want %s", pub.Channel, pub.Data, w)
+		}
+	}
+	if _, err = client.Read(100 * time.Millisecond); err == nil {
+		t.Error("read without publication succeeded")
+	}
+}
+
+func TestDialTokenExpired(t *testing.T) {
+	srv := standIn(t, "jwt")
+	defer srv.Close()
+	wsURL, _ := WebsocketURL(srv.URL)
+	_, err := Dial(wsURL, "old", time.Second)
+	var e *Error
+	if !errors.As(err, &e) || e.Code != ErrTokenExpired {
+		t.Fatalf("error %v, want code %d", err, ErrTokenExpired)
+	}
+}
+
+func TestWebsocketURL(t *testing.T) {
+	for in, want := range map[string]string{
+		"https://node1.ibax.io:8330":        "wss://node1.ibax.io:8330" + WebsocketPath,
+		"ws://127.0.0.1:8000/":              "ws://127.0.0.1:8000" + WebsocketPath,
+		"wss://node1.ibax.io/centrifugo/ws": "wss://node1.ibax.io/centrifugo/ws",
+	} {
+		got, err := WebsocketURL(in)
+		if err != nil || got != want {
+			t.Errorf("WebsocketURL(%s) = %s, %v, want %s", in, got, err, want)
+		}
+	}
+	if _, err := WebsocketURL("tcp://node1"); err == nil {
+		t.Error("tcp url accepted")
+	}
+}
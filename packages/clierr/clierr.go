@@ -0,0 +1,127 @@
+package clierr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Kind is the category of a command failure, each kind exits with its own code
+type Kind int
+
+const (
+	Unknown  Kind = iota // unclassified failure
+	Argument             // invalid command arguments or flags
+	Config               // configuration file missing or invalid
+	Auth                 // login or token refresh failed
+	RPC                  // node request failed
+	Rejected             // transaction rejected or penalized
+	NotFound             // the requested object doesn't exist
+)
+
+var kindNames = map[Kind]string{
+	Unknown:  "unknown",
+	Argument: "argument",
+	Config:   "config",
+	Auth:     "auth",
+	RPC:      "rpc",
+	Rejected: "rejected",
+	NotFound: "not_found",
+}
+
+func (k Kind) String() string {
+	if name, ok := kindNames[k]; ok {
+		return name
+	}
+	return kindNames[Unknown]
+}
+
+// ExitCode returns the process exit code of the kind: 1 unknown, 2 argument, 3 config,
+// 4 auth, 5 rpc, 6 rejected, 7 not found
+func (k Kind) ExitCode() int {
+	if _, ok := kindNames[k]; !ok {
+		k = Unknown
+	}
+	return int(k) + 1
+}
+
+// Error is a command failure with its category
+type Error struct {
+	Kind Kind
+	Msg  string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Msg
+	}
+	if e.Msg == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %s", e.Msg, e.Err.Error())
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func New(kind Kind, format string, a ...any) error {
+	return &Error{Kind: kind, Msg: fmt.Sprintf(format, a...)}
+}
+
+// Wrap returns nil if err is nil, the message is printed as "msg: err"
+func Wrap(kind Kind, err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: kind, Msg: msg, Err: err}
+}
+
+// KindOf returns the kind of the first Error in the chain of err
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return Unknown
+}
+
+// ExitCode returns the process exit code for err, 0 if err is nil
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	return KindOf(err).ExitCode()
+}
+
+// Envelope is the json form of a failure
+type Envelope struct {
+	Error struct {
+		Kind     string `json:"kind"`
+		ExitCode int    `json:"exit_code"`
+		Message  string `json:"message"`
+	} `json:"error"`
+}
+
+func NewEnvelope(err error) Envelope {
+	var env Envelope
+	kind := KindOf(err)
+	env.Error.Kind = kind.String()
+	env.Error.ExitCode = kind.ExitCode()
+	env.Error.Message = err.Error()
+	return env
+}
+
+// Fprint writes err to w, as a json envelope if asJSON is set
+func Fprint(w io.Writer, err error, asJSON bool) {
+	if err == nil {
+		return
+	}
+	if asJSON {
+		json.NewEncoder(w).Encode(NewEnvelope(err))
+		return
+	}
+	fmt.Fprintf(w, "Error: %s\n", err.Error())
+}
@@ -15,4 +15,18 @@ const (
 
 	// PublicKeyFilename name of wallet public key file
 	PublicKeyFilename = "PublicKey"
+
+	// KeystoreFilename name of wallet encrypted private key file
+	KeystoreFilename = "Keystore"
+
+	// AlgorithmFilename name of the algorithm tag file of a clear private key
+	AlgorithmFilename = "Algorithm"
+
+	// AddressBookFilename name of the address book file, next to the config file
+	AddressBookFilename = "addressbook.yml"
+
+	// PassphraseEnv environment variable holding the keystore passphrase
+	PassphraseEnv = "IBAX_PASSPHRASE"
+	// NewPassphraseEnv environment variable holding the new passphrase of account passwd
+	NewPassphraseEnv = "IBAX_NEW_PASSPHRASE"
 )
@@ -0,0 +1,159 @@
+package hdwallet
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	secp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/tyler-smith/go-bip39"
+	"strconv"
+	"strings"
+)
+
+// HardenedOffset is added to the index of a hardened path element
+const HardenedOffset uint32 = 0x80000000
+
+// DefaultPath is the BIP-44 path of the first account. IBAX has no registered coin type,
+// the secp256k1 coin type 60 is used so that the keys can be recovered by common wallets
+const DefaultPath = "m/44'/60'/0'/0/0"
+
+// masterKey is the hmac key of the BIP-32 master key generation
+var masterKey = []byte("Bitcoin seed")
+
+var (
+	ErrMnemonic   = errors.New("mnemonic invalid")
+	ErrInvalidKey = errors.New("derived key invalid, use the next index")
+)
+
+// NewMnemonic returns a BIP-39 phrase of 12 or 24 words
+func NewMnemonic(words int) (string, error) {
+	var bitSize int
+	switch words {
+	case 12:
+		bitSize = 128
+	case 24:
+		bitSize = 256
+	default:
+		return "", fmt.Errorf("mnemonic words %d invalid, use 12 or 24", words)
+	}
+	entropy, err := bip39.NewEntropy(bitSize)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// NormalizeMnemonic collapses the white space of the phrase
+func NormalizeMnemonic(mnemonic string) string {
+	return strings.Join(strings.Fields(mnemonic), " ")
+}
+
+// Seed checks the phrase and returns its BIP-39 seed, password is the optional extension word
+func Seed(mnemonic, password string) ([]byte, error) {
+	mnemonic = NormalizeMnemonic(mnemonic)
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, ErrMnemonic
+	}
+	return bip39.NewSeed(mnemonic, password), nil
+}
+
+// Path is a parsed BIP-32 derivation path
+type Path []uint32
+
+// ParsePath parses a path like m/44'/60'/0'/0/0, hardened elements end with ' or h
+func ParsePath(s string) (Path, error) {
+	elems := strings.Split(strings.TrimSpace(s), "/")
+	if len(elems) == 0 || elems[0] != "m" {
+		return nil, fmt.Errorf("path [%s] invalid, must start with m/", s)
+	}
+	path := make(Path, 0, len(elems)-1)
+	for _, e := range elems[1:] {
+		var offset uint32
+		if strings.HasSuffix(e, "'") || strings.HasSuffix(e, "h") {
+			offset = HardenedOffset
+			e = e[:len(e)-1]
+		}
+		index, err := strconv.ParseUint(e, 10, 32)
+		if err != nil || uint32(index) >= HardenedOffset {
+			return nil, fmt.Errorf("path [%s] element [%s] invalid", s, e)
+		}
+		path = append(path, uint32(index)+offset)
+	}
+	return path, nil
+}
+
+func (p Path) String() string {
+	var b strings.Builder
+	b.WriteString("m")
+	for _, index := range p {
+		if index >= HardenedOffset {
+			fmt.Fprintf(&b, "/%d'", index-HardenedOffset)
+		} else {
+			fmt.Fprintf(&b, "/%d", index)
+		}
+	}
+	return b.String()
+}
+
+// Next returns the path with the last element increased by n
+func (p Path) Next(n uint32) (Path, error) {
+	if len(p) == 0 {
+		return nil, errors.New("path has no element to increase")
+	}
+	next := make(Path, len(p))
+	copy(next, p)
+	last := next[len(next)-1]
+	hardened := last & HardenedOffset
+	if (last&^HardenedOffset)+n >= HardenedOffset {
+		return nil, errors.New("path index overflow")
+	}
+	next[len(next)-1] = ((last &^ HardenedOffset) + n) | hardened
+	return next, nil
+}
+
+// Derive returns the secp256k1 private key of the path, following BIP-32
+func Derive(seed []byte, path Path) ([]byte, error) {
+	mac := hmac.New(sha512.New, masterKey)
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key, chainCode := new(secp.ModNScalar), sum[32:]
+	if overflow := key.SetByteSlice(sum[:32]); overflow || key.IsZero() {
+		return nil, ErrInvalidKey
+	}
+	for _, index := range path {
+		var err error
+		key, chainCode, err = deriveChild(key, chainCode, index)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	b := key.Bytes()
+	return b[:], nil
+}
+
+func deriveChild(key *secp.ModNScalar, chainCode []byte, index uint32) (*secp.ModNScalar, []byte, error) {
+	data := make([]byte, 0, 37)
+	if index >= HardenedOffset {
+		b := key.Bytes()
+		data = append(append(data, 0), b[:]...)
+	} else {
+		b := key.Bytes()
+		data = append(data, secp.PrivKeyFromBytes(b[:]).PubKey().SerializeCompressed()...)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	mac := hmac.New(sha512.New, chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	child := new(secp.ModNScalar)
+	if overflow := child.SetByteSlice(sum[:32]); overflow {
+		return nil, nil, ErrInvalidKey
+	}
+	child.Add(key)
+	if child.IsZero() {
+		return nil, nil, ErrInvalidKey
+	}
+	return child, sum[32:], nil
+}
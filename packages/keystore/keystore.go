@@ -0,0 +1,236 @@
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/scrypt"
+	"os"
+)
+
+// Version is the keystore format version written by Encrypt
+const Version = 1
+
+const (
+	cipherName = "aes-256-gcm"
+	kdfName    = "scrypt"
+
+	// scrypt parameters, the derived key is split in the aes-256 key and the mac key
+	scryptN     = 1 << 18
+	scryptR     = 8
+	scryptP     = 1
+	scryptDKLen = 64
+	saltLen     = 32
+
+	// limits of the scrypt parameters of a file, the memory of scrypt is 128*N*R bytes
+	scryptMaxN      = 1 << 20
+	scryptMaxRP     = 16
+	scryptMaxMemory = 1 << 30
+)
+
+var (
+	ErrDecrypt = errors.New("could not decrypt key with given passphrase")
+	ErrVersion = errors.New("keystore version not supported")
+)
+
+// Keystore is the versioned json envelope of a passphrase protected private key
+//
+//	{
+//		"version": 1,
+//		"address": "xxxx-xxxx-xxxx-xxxx-xxxx",
+//		"key_id": n,
+//		"public_key": "hex",
+//		"cryptoer": "str",		key algorithm of the private key, empty for keystores of older versions
+//		"hasher": "str",		hash algorithm of the address
+//		"crypto": {
+//			"cipher": "aes-256-gcm",
+//			"cipher_text": "hex",
+//			"nonce": "hex",
+//			"kdf": "scrypt",
+//			"kdf_params": {"n": n, "r": n, "p": n, "dk_len": n, "salt": "hex"},
+//			"mac": "hex"			hmac-sha256 of the cipher text with the second half of the derived key
+//		}
+//	}
+type Keystore struct {
+	Version   int    `json:"version"`
+	Address   string `json:"address"`
+	KeyId     int64  `json:"key_id"`
+	PublicKey string `json:"public_key"`
+	Cryptoer  string `json:"cryptoer,omitempty"`
+	Hasher    string `json:"hasher,omitempty"`
+	Crypto    Crypto `json:"crypto"`
+}
+
+type Crypto struct {
+	Cipher     string    `json:"cipher"`
+	CipherText string    `json:"cipher_text"`
+	Nonce      string    `json:"nonce"`
+	KDF        string    `json:"kdf"`
+	KDFParams  KDFParams `json:"kdf_params"`
+	MAC        string    `json:"mac"`
+}
+
+type KDFParams struct {
+	N     int    `json:"n"`
+	R     int    `json:"r"`
+	P     int    `json:"p"`
+	DKLen int    `json:"dk_len"`
+	Salt  string `json:"salt"`
+}
+
+// Encrypt protects the private key with the passphrase, the public information is stored in clear.
+// The caller sets the algorithm tag
+func Encrypt(privateKey []byte, passphrase string, publicKey string, keyId int64, address string) (*Keystore, error) {
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	params := KDFParams{N: scryptN, R: scryptR, P: scryptP, DKLen: scryptDKLen, Salt: hex.EncodeToString(salt)}
+	derived, err := scrypt.Key([]byte(passphrase), salt, params.N, params.R, params.P, params.DKLen)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(derived[:32])
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	cipherText := gcm.Seal(nil, nonce, privateKey, nil)
+
+	return &Keystore{
+		Version:   Version,
+		Address:   address,
+		KeyId:     keyId,
+		PublicKey: publicKey,
+		Crypto: Crypto{
+			Cipher:     cipherName,
+			CipherText: hex.EncodeToString(cipherText),
+			Nonce:      hex.EncodeToString(nonce),
+			KDF:        kdfName,
+			KDFParams:  params,
+			MAC:        hex.EncodeToString(mac(derived[32:], cipherText)),
+		},
+	}, nil
+}
+
+// Decrypt returns the private key, ErrDecrypt is returned for a wrong passphrase
+func (k *Keystore) Decrypt(passphrase string) ([]byte, error) {
+	if k.Version != Version {
+		return nil, fmt.Errorf("%w: %d", ErrVersion, k.Version)
+	}
+	c := k.Crypto
+	if c.Cipher != cipherName || c.KDF != kdfName {
+		return nil, fmt.Errorf("cipher [%s] or kdf [%s] not supported", c.Cipher, c.KDF)
+	}
+	salt, err := hex.DecodeString(c.KDFParams.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("salt invalid: %w", err)
+	}
+	cipherText, err := hex.DecodeString(c.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("cipher text invalid: %w", err)
+	}
+	nonce, err := hex.DecodeString(c.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("nonce invalid: %w", err)
+	}
+	expected, err := hex.DecodeString(c.MAC)
+	if err != nil {
+		return nil, fmt.Errorf("mac invalid: %w", err)
+	}
+	if c.KDFParams.DKLen != scryptDKLen {
+		return nil, fmt.Errorf("derived key length %d not supported", c.KDFParams.DKLen)
+	}
+	if err = checkScrypt(c.KDFParams); err != nil {
+		return nil, err
+	}
+
+	derived, err := scrypt.Key([]byte(passphrase), salt, c.KDFParams.N, c.KDFParams.R, c.KDFParams.P, c.KDFParams.DKLen)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(mac(derived[32:], cipherText), expected) {
+		return nil, ErrDecrypt
+	}
+	gcm, err := newGCM(derived[:32])
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("nonce length %d invalid", len(nonce))
+	}
+	privateKey, err := gcm.Open(nil, nonce, cipherText, nil)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return privateKey, nil
+}
+
+// checkScrypt rejects the parameters that make scrypt fail or take too much memory
+func checkScrypt(p KDFParams) error {
+	n, r := p.N, p.R
+	if n <= 1 || n > scryptMaxN || n&(n-1) != 0 {
+		return fmt.Errorf("scrypt N %d invalid, a power of two up to %d", n, scryptMaxN)
+	}
+	if r < 1 || p.P < 1 || r > scryptMaxRP || p.P > scryptMaxRP || r*p.P > scryptMaxRP {
+		return fmt.Errorf("scrypt r %d and p %d invalid, r*p up to %d", r, p.P, scryptMaxRP)
+	}
+	if 128*n*r > scryptMaxMemory {
+		return fmt.Errorf("scrypt N %d and r %d need more than %d bytes", n, r, scryptMaxMemory)
+	}
+	return nil
+}
+
+// Load reads a keystore file
+func Load(filename string) (*Keystore, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+func Parse(data []byte) (*Keystore, error) {
+	var k Keystore
+	if err := json.Unmarshal(data, &k); err != nil {
+		return nil, fmt.Errorf("keystore invalid: %w", err)
+	}
+	if k.Version == 0 || k.Crypto.CipherText == "" {
+		return nil, errors.New("keystore invalid: missing version or cipher text")
+	}
+	return &k, nil
+}
+
+// IsKeystore reports whether the data looks like a keystore envelope
+func IsKeystore(data []byte) bool {
+	_, err := Parse(data)
+	return err == nil
+}
+
+func (k *Keystore) Marshal() ([]byte, error) {
+	return json.MarshalIndent(k, "", "    ")
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func mac(key, cipherText []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(cipherText)
+	return h.Sum(nil)
+}
@@ -0,0 +1,90 @@
+package money
+
+import (
+	"fmt"
+	"github.com/shopspring/decimal"
+	"strings"
+	"unicode"
+)
+
+// SmallestPrefix is put before the token symbol for its smallest unit: IBAX and QIBAX
+const SmallestPrefix = "Q"
+
+// Token is the token of an ecosystem, 1 token is 10^Digits of its smallest unit
+type Token struct {
+	Symbol string
+	Digits int32
+}
+
+// Split returns the number and the unit of an amount like "1.5IBAX" or "1.5 IBAX".
+// The unit is the letters at the end, so that the exponent of "1e12" stays in the number
+func Split(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	i := strings.LastIndexFunc(s, func(r rune) bool { return !unicode.IsLetter(r) }) + 1
+	return strings.TrimSpace(s[:i]), s[i:]
+}
+
+// HasUnit reports whether the amount is given with a unit
+func HasUnit(s string) bool {
+	_, unit := Split(s)
+	return unit != ""
+}
+
+// Smallest returns the amount in the smallest unit, an amount without unit is in the smallest unit already
+func (t Token) Smallest(s string) (decimal.Decimal, error) {
+	return t.parse(s, false)
+}
+
+// Whole returns the amount in tokens, an amount without unit is in tokens already
+func (t Token) Whole(s string) (decimal.Decimal, error) {
+	value, err := t.parse(s, true)
+	if err != nil {
+		return value, err
+	}
+	return value.Shift(-t.Digits), nil
+}
+
+func (t Token) parse(s string, plainWhole bool) (decimal.Decimal, error) {
+	number, unit := Split(s)
+	value, err := decimal.NewFromString(number)
+	if err != nil {
+		return value, fmt.Errorf("[%s] isn't an amount", s)
+	}
+	whole := plainWhole
+	switch {
+	case unit == "":
+	case t.Symbol != "" && strings.EqualFold(unit, t.Symbol):
+		whole = true
+	case t.Symbol != "" && strings.EqualFold(unit, SmallestPrefix+t.Symbol):
+		whole = false
+	default:
+		return value, fmt.Errorf("unit [%s] of [%s] unknown, %s", unit, s, t.Units())
+	}
+	if whole {
+		value = value.Shift(t.Digits)
+		if !value.IsInteger() {
+			return value, fmt.Errorf("[%s] has more than %d decimal places", s, t.Digits)
+		}
+	}
+	if !value.IsInteger() {
+		return value, fmt.Errorf("[%s] isn't an integer of the smallest unit", s)
+	}
+	return value, nil
+}
+
+// Units returns the units of the token for messages
+func (t Token) Units() string {
+	if t.Symbol == "" {
+		return "the token has no symbol, give the amount without unit"
+	}
+	return fmt.Sprintf("units: %s (10^%d) || %s%s (smallest unit)", t.Symbol, t.Digits, SmallestPrefix, t.Symbol)
+}
+
+// Format returns the amount of the smallest unit in tokens with the symbol: "1.5 IBAX"
+func (t Token) Format(smallest string) string {
+	value, err := decimal.NewFromString(smallest)
+	if err != nil {
+		return smallest
+	}
+	return strings.TrimSpace(value.Shift(-t.Digits).String() + " " + t.Symbol)
+}
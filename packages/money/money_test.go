@@ -0,0 +1,72 @@
+package money
+
+import "testing"
+
+var ibax = Token{Symbol: "IBAX", Digits: 12}
+
+func TestSplit(t *testing.T) {
+	for _, c := range []struct {
+		in, number, unit string
+	}{
+		{"1000", "1000", ""},
+		{"1.5IBAX", "1.5", "IBAX"},
+		{" 1.5 IBAX ", "1.5", "IBAX"},
+		{"1e12", "1e12", ""},
+		{"1.5e3QIBAX", "1.5e3", "QIBAX"},
+		{"-2ibax", "-2", "ibax"},
+		{"IBAX", "", "IBAX"},
+		{"", "", ""},
+	} {
+		number, unit := Split(c.in)
+		if number != c.number || unit != c.unit {
+			t.Errorf("Split(%q) = %q, %q, want %q, %q", c.in, number, unit, c.number, c.unit)
+		}
+	}
+}
+
+func TestSmallest(t *testing.T) {
+	for _, c := range []struct {
+		in, want string
+	}{
+		{"1000", "1000"},
+		{"1e12", "1000000000000"},
+		{"1.5IBAX", "1500000000000"},
+		{"1.5 ibax", "1500000000000"},
+		{"25QIBAX", "25"},
+		{"0.000000000001IBAX", "1"},
+	} {
+		got, err := ibax.Smallest(c.in)
+		if err != nil || got.String() != c.want {
+			t.Errorf("Smallest(%q) = %s, %v, want %s", c.in, got, err, c.want)
+		}
+	}
+	for _, in := range []string{"1.5", "1.5QIBAX", "0.0000000000001IBAX", "1ETH", "abc", "IBAX"} {
+		if got, err := ibax.Smallest(in); err == nil {
+			t.Errorf("Smallest(%q) = %s, want an error", in, got)
+		}
+	}
+}
+
+func TestWhole(t *testing.T) {
+	for _, c := range []struct {
+		in, want string
+	}{
+		{"1.5", "1.5"},
+		{"2e-1", "0.2"},
+		{"1.5IBAX", "1.5"},
+		{"1500000000000QIBAX", "1.5"},
+	} {
+		got, err := ibax.Whole(c.in)
+		if err != nil || got.String() != c.want {
+			t.Errorf("Whole(%q) = %s, %v, want %s", c.in, got, err, c.want)
+		}
+	}
+	for _, in := range []string{"0.0000000000001", "1.5QIBAX", "1ETH"} {
+		if got, err := ibax.Whole(in); err == nil {
+			t.Errorf("Whole(%q) = %s, want an error", in, got)
+		}
+	}
+	if _, err := (Token{}).Whole("1IBAX"); err == nil {
+		t.Error("Whole of a token without symbol accepted a unit")
+	}
+}
@@ -0,0 +1,299 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+type Format string
+
+const (
+	JSON       Format = "json"        // compact json, one document per line
+	PrettyJSON Format = "pretty-json" // indented json
+	YAML       Format = "yaml"
+	Table      Format = "table" // columns are derived from the result
+	CSV        Format = "csv"
+	Raw        Format = "raw" // scalars as is, everything else as compact json
+)
+
+// Formats is the list of supported output formats
+var Formats = []Format{JSON, PrettyJSON, YAML, Table, CSV, Raw}
+
+// listKey is the key holding the rows of list results, such as getList, getContracts, getSections
+const listKey = "list"
+
+func Parse(s string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == strings.ToLower(s) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("output format [%s] is not supported, available: %s", s, Names())
+}
+
+func Names() string {
+	var names []string
+	for _, f := range Formats {
+		names = append(names, string(f))
+	}
+	return strings.Join(names, " | ")
+}
+
+// Print writes v to stdout in the specified format
+func Print(format Format, v any) error {
+	return Fprint(os.Stdout, format, v)
+}
+
+// Fprint writes v to w in the specified format
+func Fprint(w io.Writer, format Format, v any) error {
+	switch format {
+	case JSON, "":
+		return json.NewEncoder(w).Encode(v)
+	case PrettyJSON:
+		str, err := json.MarshalIndent(v, "", "    ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", str)
+		return err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	value, err := normalize(data)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case YAML:
+		return yaml.NewEncoder(w).Encode(value)
+	case Table:
+		return writeTable(w, value, keyOrder(data))
+	case CSV:
+		return writeCSV(w, value, keyOrder(data))
+	case Raw:
+		if isScalar(value) {
+			_, err = fmt.Fprintln(w, cell(value))
+			return err
+		}
+		return json.NewEncoder(w).Encode(value)
+	}
+	return fmt.Errorf("output format [%s] is not supported, available: %s", format, Names())
+}
+
+// Normalize converts v into generic maps, slices and scalars, honouring the json tags of structs.
+// Integers are kept as int64 so that key ids and amounts don't lose precision.
+func Normalize(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return normalize(data)
+}
+
+func normalize(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	return convertNumbers(value), nil
+}
+
+func convertNumbers(v any) any {
+	switch val := v.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		if f, err := val.Float64(); err == nil {
+			return f
+		}
+		return val.String()
+	case map[string]any:
+		for k, item := range val {
+			val[k] = convertNumbers(item)
+		}
+	case []any:
+		for k, item := range val {
+			val[k] = convertNumbers(item)
+		}
+	}
+	return v
+}
+
+// keyOrder returns the position of the object keys in the order of their first appearance in data,
+// so that the columns of struct results follow the field order
+func keyOrder(data []byte) map[string]int {
+	type level struct {
+		object    bool
+		expectKey bool
+	}
+	var (
+		order = make(map[string]int)
+		stack []level
+	)
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return order
+		}
+		top := len(stack) - 1
+		switch t := tok.(type) {
+		case json.Delim:
+			switch t {
+			case '{', '[':
+				stack = append(stack, level{object: t == '{', expectKey: true})
+				continue
+			default:
+				stack = stack[:top]
+				top--
+			}
+		case string:
+			if top >= 0 && stack[top].object && stack[top].expectKey {
+				if _, ok := order[t]; !ok {
+					order[t] = len(order)
+				}
+				stack[top].expectKey = false
+				continue
+			}
+		}
+		// a value is complete, the enclosing object expects the next key
+		if top >= 0 && stack[top].object {
+			stack[top].expectKey = true
+		}
+	}
+}
+
+func isScalar(v any) bool {
+	switch v.(type) {
+	case map[string]any, []any:
+		return false
+	}
+	return true
+}
+
+// rows derives the table header and rows from a normalized value.
+// Arrays of objects and objects holding such an array under "list" are shown one row per element,
+// any other object is shown as KEY/VALUE pairs.
+func rows(v any, order map[string]int) (header []string, body [][]string) {
+	switch val := v.(type) {
+	case []any:
+		return objectRows(val, order)
+	case map[string]any:
+		if list, ok := val[listKey].([]any); ok {
+			return objectRows(list, order)
+		}
+		header = []string{"KEY", "VALUE"}
+		for _, k := range sortedKeys(val, order) {
+			body = append(body, []string{k, cell(val[k])})
+		}
+		return
+	}
+	return []string{"VALUE"}, [][]string{{cell(v)}}
+}
+
+func objectRows(list []any, order map[string]int) (header []string, body [][]string) {
+	seen := make(map[string]bool)
+	for _, item := range list {
+		if m, ok := item.(map[string]any); ok {
+			for _, k := range sortedKeys(m, order) {
+				if !seen[k] {
+					seen[k] = true
+					header = append(header, k)
+				}
+			}
+		}
+	}
+	if len(header) == 0 {
+		header = []string{"VALUE"}
+		for _, item := range list {
+			body = append(body, []string{cell(item)})
+		}
+		return
+	}
+	for _, item := range list {
+		m, _ := item.(map[string]any)
+		row := make([]string, len(header))
+		for i, k := range header {
+			if val, ok := m[k]; ok {
+				row[i] = cell(val)
+			}
+		}
+		body = append(body, row)
+	}
+	return
+}
+
+// sortedKeys returns the keys with "id" in front, then in the order of appearance.
+// Maps are marshalled with sorted keys, so their keys remain in alphabetical order
+func sortedKeys(m map[string]any, order map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i] == "id" || keys[j] == "id" {
+			return keys[i] == "id"
+		}
+		if order[keys[i]] != order[keys[j]] {
+			return order[keys[i]] < order[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+func cell(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case map[string]any, []any:
+		data, _ := json.Marshal(val)
+		return string(data)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func writeTable(w io.Writer, v any, order map[string]int) error {
+	header, body := rows(v, order)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	upper := make([]string, len(header))
+	for i, h := range header {
+		upper[i] = strings.ToUpper(h)
+	}
+	fmt.Fprintln(tw, strings.Join(upper, "\t"))
+	for _, row := range body {
+		for i, c := range row {
+			row[i] = strings.NewReplacer("\t", " ", "\n", " ").Replace(c)
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func writeCSV(w io.Writer, v any, order map[string]int) error {
+	header, body := rows(v, order)
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(body); err != nil {
+		return err
+	}
+	return cw.Error()
+}
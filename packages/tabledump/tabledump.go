@@ -0,0 +1,414 @@
+// Package tabledump writes the rows of an ecosystem table, as returned by getList, to a csv file,
+// a json lines file or a table of a sqlite database, and keeps the state of the dump so that
+// a later dump appends only the new rows.
+//
+// The values of getList are strings, the column types of getTable convert them:
+//
+//	type			ndjson		sqlite
+//	number			number		INTEGER
+//	double			number		REAL
+//	boolean			bool		INTEGER
+//	money			string		TEXT, a money value doesn't fit in an INTEGER
+//	bytea			hex string	BLOB
+//	others			string		TEXT
+//
+// NULL is written as null, and as an empty field in csv.
+//
+// The state of a csv or ndjson file is kept in <file>.sync.json, the state of a sqlite table in
+// the ibax_sync table of the database.
+package tabledump
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	_ "github.com/mattn/go-sqlite3"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Format is the format of the dump
+type Format string
+
+const (
+	CSV    Format = "csv"
+	NDJSON Format = "ndjson"
+	SQLite Format = "sqlite"
+)
+
+// null is the value of NULL in the rows of getList
+const null = "NULL"
+
+// ParseFormat returns the format of the name, or of the file extension when the name is empty.
+// It is NDJSON when both are empty.
+func ParseFormat(name, path string) (Format, error) {
+	if name == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			return CSV, nil
+		case ".db", ".sqlite", ".sqlite3":
+			return SQLite, nil
+		}
+		return NDJSON, nil
+	}
+	switch f := Format(strings.ToLower(name)); f {
+	case CSV, NDJSON, SQLite:
+		return f, nil
+	case "json", "jsonl":
+		return NDJSON, nil
+	}
+	return "", fmt.Errorf("unknown format %s, one of csv, ndjson, sqlite", name)
+}
+
+// Column is a column of the dumped table
+type Column struct {
+	Name string
+	Type string
+}
+
+// State is the position of the dump, the next rows are after the row of Value and Id
+type State struct {
+	Table   string   `json:"table"`
+	Where   string   `json:"where"`
+	SyncBy  string   `json:"sync_by"`
+	Columns []string `json:"columns"`
+	Value   string   `json:"value"`
+	Id      int64    `json:"id"`
+	Rows    int64    `json:"rows"`
+}
+
+// Writer writes the pages of the rows
+type Writer interface {
+	// Write writes the rows of a page and then the state after them
+	Write(rows []map[string]string, state *State) error
+	Close() error
+}
+
+// Value converts the value of getList to the value of the column type, nil for NULL
+func Value(typ, s string) (any, error) {
+	if s == null {
+		return nil, nil
+	}
+	switch typ {
+	case "number", "bigint", "integer", "smallint":
+		return strconv.ParseInt(s, 10, 64)
+	case "double", "real":
+		return strconv.ParseFloat(s, 64)
+	case "boolean":
+		return strconv.ParseBool(s)
+	case "bytea":
+		return hex.DecodeString(s)
+	}
+	return s, nil
+}
+
+// sqliteType returns the type of the sqlite column of the column type
+func sqliteType(typ string) string {
+	switch typ {
+	case "number", "bigint", "integer", "smallint", "boolean":
+		return "INTEGER"
+	case "double", "real":
+		return "REAL"
+	case "bytea":
+		return "BLOB"
+	}
+	return "TEXT"
+}
+
+func statePath(path string) string {
+	return path + ".sync.json"
+}
+
+// ReadState returns the state of the previous dump to the file, or to the sqlite table,
+// nil when there is none
+func ReadState(format Format, path, table string) (*State, error) {
+	if format == SQLite {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		w, err := openSQLite(path, table, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer w.Close()
+		return w.state()
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	data, err := os.ReadFile(statePath(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	state := &State{}
+	if err = json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("state %s: %w", statePath(path), err)
+	}
+	return state, nil
+}
+
+// writeState replaces the state file, so that a crash leaves the previous one
+func writeState(path string, state *State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	tmp := statePath(path) + ".tmp"
+	if err = os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, statePath(path))
+}
+
+// Open opens the dump of the columns to the file, the rows are appended to the previous dump when appending,
+// otherwise the file, or the sqlite table, is replaced. The state is kept only for a file,
+// csv and ndjson are written to w when path is empty.
+func Open(format Format, w io.Writer, path, table string, columns []Column, appending bool) (Writer, error) {
+	if format == SQLite {
+		if path == "" {
+			return nil, errors.New("sqlite needs a file")
+		}
+		s, err := openSQLite(path, table, columns)
+		if err != nil {
+			return nil, err
+		}
+		if !appending {
+			err = s.reset()
+		}
+		if err == nil {
+			err = s.create()
+		}
+		if err != nil {
+			s.Close()
+			return nil, err
+		}
+		return s, nil
+	}
+
+	var file *os.File
+	header := true
+	if path != "" {
+		flag := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+		if appending {
+			flag = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+			if fi, err := os.Stat(path); err == nil && fi.Size() > 0 {
+				header = false
+			}
+		} else if err := os.Remove(statePath(path)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		var err error
+		if file, err = os.OpenFile(path, flag, 0644); err != nil {
+			return nil, err
+		}
+		w = file
+	}
+	f := &fileWriter{
+		file:    file,
+		path:    path,
+		buf:     bufio.NewWriter(w),
+		columns: columns,
+		csv:     format == CSV,
+	}
+	if f.csv && header {
+		names := make([]string, len(columns))
+		for i, c := range columns {
+			names[i] = c.Name
+		}
+		cw := csv.NewWriter(f.buf)
+		if err := cw.Write(names); err != nil {
+			f.Close()
+			return nil, err
+		}
+		cw.Flush()
+	}
+	return f, nil
+}
+
+// fileWriter writes csv or json lines
+type fileWriter struct {
+	file    *os.File
+	path    string
+	buf     *bufio.Writer
+	columns []Column
+	csv     bool
+}
+
+func (f *fileWriter) Write(rows []map[string]string, state *State) error {
+	if f.csv {
+		cw := csv.NewWriter(f.buf)
+		record := make([]string, len(f.columns))
+		for _, row := range rows {
+			for i, c := range f.columns {
+				if record[i] = row[c.Name]; record[i] == null {
+					record[i] = ""
+				}
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+	} else {
+		for _, row := range rows {
+			// the keys are written in the order of the columns, json of a map would sort them
+			f.buf.WriteByte('{')
+			for i, c := range f.columns {
+				v, err := Value(c.Type, row[c.Name])
+				if err != nil {
+					return fmt.Errorf("column %s: %w", c.Name, err)
+				}
+				if c.Type == "bytea" && v != nil {
+					v = row[c.Name]
+				}
+				name, _ := json.Marshal(c.Name)
+				value, err := json.Marshal(v)
+				if err != nil {
+					return fmt.Errorf("column %s: %w", c.Name, err)
+				}
+				if i > 0 {
+					f.buf.WriteByte(',')
+				}
+				f.buf.Write(name)
+				f.buf.WriteByte(':')
+				f.buf.Write(value)
+			}
+			f.buf.WriteString("}\n")
+		}
+	}
+	if err := f.buf.Flush(); err != nil {
+		return err
+	}
+	if f.path == "" {
+		return nil
+	}
+	return writeState(f.path, state)
+}
+
+func (f *fileWriter) Close() error {
+	err := f.buf.Flush()
+	if f.file != nil {
+		if cerr := f.file.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// sqliteWriter upserts the rows by id into a table of the database
+type sqliteWriter struct {
+	db      *sql.DB
+	table   string
+	columns []Column
+}
+
+func quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func openSQLite(path, table string, columns []Column) (*sqliteWriter, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS ibax_sync (name TEXT PRIMARY KEY, state TEXT NOT NULL)`)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &sqliteWriter{db: db, table: table, columns: columns}, nil
+}
+
+func (s *sqliteWriter) state() (*State, error) {
+	var data string
+	err := s.db.QueryRow(`SELECT state FROM ibax_sync WHERE name = ?`, s.table).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	state := &State{}
+	if err = json.Unmarshal([]byte(data), state); err != nil {
+		return nil, fmt.Errorf("state of %s: %w", s.table, err)
+	}
+	return state, nil
+}
+
+// reset drops the table and its state
+func (s *sqliteWriter) reset() error {
+	if _, err := s.db.Exec(`DROP TABLE IF EXISTS ` + quote(s.table)); err != nil {
+		return err
+	}
+	_, err := s.db.Exec(`DELETE FROM ibax_sync WHERE name = ?`, s.table)
+	return err
+}
+
+func (s *sqliteWriter) create() error {
+	defs := make([]string, len(s.columns))
+	for i, c := range s.columns {
+		defs[i] = quote(c.Name) + " " + sqliteType(c.Type)
+		if c.Name == "id" {
+			defs[i] += " PRIMARY KEY"
+		}
+	}
+	_, err := s.db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (%s)`, quote(s.table), strings.Join(defs, ", ")))
+	return err
+}
+
+func (s *sqliteWriter) Write(rows []map[string]string, state *State) error {
+	names := make([]string, len(s.columns))
+	for i, c := range s.columns {
+		names[i] = quote(c.Name)
+	}
+	insert := fmt.Sprintf(`INSERT OR REPLACE INTO %s (%s) VALUES (%s)`, quote(s.table),
+		strings.Join(names, ", "), strings.TrimSuffix(strings.Repeat("?, ", len(names)), ", "))
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	stmt, err := tx.Prepare(insert)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	values := make([]any, len(s.columns))
+	for _, row := range rows {
+		for i, c := range s.columns {
+			if values[i], err = Value(c.Type, row[c.Name]); err != nil {
+				return fmt.Errorf("column %s: %w", c.Name, err)
+			}
+		}
+		if _, err = stmt.Exec(values...); err != nil {
+			return err
+		}
+	}
+	_, err = tx.Exec(`INSERT OR REPLACE INTO ibax_sync (name, state) VALUES (?, ?)`, s.table, string(data))
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *sqliteWriter) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,223 @@
+package tabledump
+
+import (
+	"bytes"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testColumns = []Column{
+	{Name: "id", Type: "number"},
+	{Name: "name", Type: "varchar"},
+	{Name: "amount", Type: "money"},
+	{Name: "pub", Type: "bytea"},
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, c := range []struct {
+		name, path string
+		want       Format
+	}{
+		{"", "", NDJSON},
+		{"", "keys.csv", CSV},
+		{"", "KEYS.CSV", CSV},
+		{"", "ecosystem.db", SQLite},
+		{"", "ecosystem.sqlite3", SQLite},
+		{"", "keys.json", NDJSON},
+		{"csv", "keys.db", CSV},
+		{"SQLite", "", SQLite},
+		{"jsonl", "", NDJSON},
+	} {
+		got, err := ParseFormat(c.name, c.path)
+		if err != nil || got != c.want {
+			t.Errorf("ParseFormat(%q, %q) = %s, %v, want %s", c.name, c.path, got, err, c.want)
+		}
+	}
+	if got, err := ParseFormat("xml", ""); err == nil {
+		t.Errorf("ParseFormat(xml) = %s, want an error", got)
+	}
+}
+
+func TestValue(t *testing.T) {
+	for _, c := range []struct {
+		typ, in string
+		want    any
+	}{
+		{"number", "42", int64(42)},
+		{"double", "1.5", 1.5},
+		{"boolean", "true", true},
+		{"bytea", "0a0b", []byte{10, 11}},
+		{"money", "1000000000000000000000", "1000000000000000000000"},
+		{"varchar", "john", "john"},
+		{"number", "NULL", nil},
+		{"varchar", "NULL", nil},
+	} {
+		got, err := Value(c.typ, c.in)
+		if err != nil || !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Value(%q, %q) = %#v, %v, want %#v", c.typ, c.in, got, err, c.want)
+		}
+	}
+	for _, c := range [][2]string{{"number", "1.5"}, {"boolean", "yes"}, {"bytea", "zz"}} {
+		if got, err := Value(c[0], c[1]); err == nil {
+			t.Errorf("Value(%q, %q) = %#v, want an error", c[0], c[1], got)
+		}
+	}
+}
+
+func testRows() []map[string]string {
+	return []map[string]string{
+		{"id": "1", "name": "john", "amount": "100", "pub": "0a0b"},
+		{"id": "2", "name": "NULL", "amount": "NULL", "pub": "NULL"},
+	}
+}
+
+func TestCSV(t *testing.T) {
+	var out bytes.Buffer
+	w, err := Open(CSV, &out, "", "", testColumns, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Write(testRows(), &State{}); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want := "id,name,amount,pub\n1,john,100,0a0b\n2,,,\n"
+	if out.String() != want {
+		t.Errorf("csv = %q, want %q", out.String(), want)
+	}
+}
+
+func TestNDJSON(t *testing.T) {
+	var out bytes.Buffer
+	w, err := Open(NDJSON, &out, "", "", testColumns, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Write(testRows(), &State{}); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	want := `{"id":1,"name":"john","amount":"100","pub":"0a0b"}` + "\n" +
+		`{"id":2,"name":null,"amount":null,"pub":null}` + "\n"
+	if out.String() != want {
+		t.Errorf("ndjson = %q, want %q", out.String(), want)
+	}
+}
+
+func TestFileState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.csv")
+	if state, err := ReadState(CSV, path, ""); err != nil || state != nil {
+		t.Fatalf("ReadState of a missing file = %+v, %v, want nil", state, err)
+	}
+	state := &State{Table: "keys", Where: `{"id":{"$gt":0}}`, SyncBy: "id", Columns: []string{"id", "name"}, Value: "2", Id: 2, Rows: 2}
+	columns := testColumns[:2]
+	w, err := Open(CSV, nil, path, "", columns, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Write(testRows(), state); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	got, err := ReadState(CSV, path, "")
+	if err != nil || !reflect.DeepEqual(got, state) {
+		t.Fatalf("ReadState = %+v, %v, want %+v", got, err, state)
+	}
+
+	// appending doesn't write the header again
+	if w, err = Open(CSV, nil, path, "", columns, true); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Write([]map[string]string{{"id": "3", "name": "ann"}}, state); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "id,name\n1,john\n2,\n3,ann\n" {
+		t.Errorf("appended csv = %q, %v", data, err)
+	}
+
+	// a new dump removes the state
+	if w, err = Open(CSV, nil, path, "", columns, false); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	if got, err = ReadState(CSV, path, ""); err != nil || got != nil {
+		t.Errorf("ReadState after a new dump = %+v, %v, want nil", got, err)
+	}
+}
+
+func TestSQLite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ecosystem.db")
+	if state, err := ReadState(SQLite, path, "keys"); err != nil || state != nil {
+		t.Fatalf("ReadState of a missing database = %+v, %v, want nil", state, err)
+	}
+	state := &State{Table: "@1keys", SyncBy: "id", Columns: []string{"id", "name", "amount", "pub"}, Value: "2", Id: 2, Rows: 2}
+	w, err := Open(SQLite, nil, path, "keys", testColumns, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Write(testRows(), state); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	got, err := ReadState(SQLite, path, "keys")
+	if err != nil || !reflect.DeepEqual(got, state) {
+		t.Fatalf("ReadState = %+v, %v, want %+v", got, err, state)
+	}
+
+	// a row written again is replaced by id
+	if w, err = Open(SQLite, nil, path, "keys", testColumns, true); err != nil {
+		t.Fatal(err)
+	}
+	state.Rows = 3
+	rows := []map[string]string{{"id": "1", "name": "john", "amount": "250", "pub": "0c"}}
+	if err = w.Write(rows, state); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var count int
+	if err = db.QueryRow(`SELECT count(*) FROM keys`).Scan(&count); err != nil || count != 2 {
+		t.Errorf("rows = %d, %v, want 2", count, err)
+	}
+	var amount string
+	var pub []byte
+	if err = db.QueryRow(`SELECT amount, pub FROM keys WHERE id = 1`).Scan(&amount, &pub); err != nil ||
+		amount != "250" || !bytes.Equal(pub, []byte{12}) {
+		t.Errorf("row 1 = %s, %x, %v, want 250, 0c", amount, pub, err)
+	}
+	var name sql.NullString
+	if err = db.QueryRow(`SELECT name FROM keys WHERE id = 2`).Scan(&name); err != nil || name.Valid {
+		t.Errorf("name of row 2 = %+v, %v, want NULL", name, err)
+	}
+	if got, err = ReadState(SQLite, path, "keys"); err != nil || got.Rows != 3 {
+		t.Errorf("ReadState after the upsert = %+v, %v, want 3 rows", got, err)
+	}
+
+	// a new dump drops the table and its state
+	if w, err = Open(SQLite, nil, path, "keys", testColumns, false); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	if got, err = ReadState(SQLite, path, "keys"); err != nil || got != nil {
+		t.Errorf("ReadState after a new dump = %+v, %v, want nil", got, err)
+	}
+	if err = db.QueryRow(`SELECT count(*) FROM keys`).Scan(&count); err != nil || count != 0 {
+		t.Errorf("rows after a new dump = %d, %v, want 0", count, err)
+	}
+	if _, err = Open(SQLite, nil, "", "keys", testColumns, false); err == nil || !strings.Contains(err.Error(), "file") {
+		t.Errorf("Open of sqlite without a file = %v, want an error", err)
+	}
+}
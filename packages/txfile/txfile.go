@@ -0,0 +1,331 @@
+// Package txfile is the file of a transaction that is built, signed and sent by separate commands,
+// so that the private key can stay on a machine without network.
+//
+// The file is a json object:
+//
+//	{
+//		"version": 1,					format version, files of other versions are rejected
+//		"type": "contract",				transaction type
+//		"status": "unsigned",			unsigned | signed
+//		"contract": "@1TokensSend",		contract name
+//		"contract_id": 42,				contract id in the VM of the node
+//		"params": {...},				decoded contract params, bytes are written as 0x hex
+//		"expedite": "",					expedite fee
+//		"ecosystem_id": 1,
+//		"key_id": -123,					key id of the signer
+//		"account": "xxxx-...-xxxx",		address of the signer
+//		"network_id": 1,
+//		"time": 1681439511,				unix time of the transaction
+//		"expires": "2023-04-15T...",	the node rejects the transaction after this time
+//		"cryptoer": "ECC_Secp256k1",	algorithms of the signature
+//		"hasher": "KECCAK256",
+//		"public_key": "hex",			set by signing
+//		"payload": "hex",				msgpack of the transaction, the signed bytes
+//		"hash": "hex",					set by signing, transaction hash
+//		"signature": "hex"				set by signing
+//	}
+//
+// The payload is the transaction, the other fields show it for review. Reading a file checks
+// that the fields match the payload, so an edited file is rejected instead of signing something else.
+package txfile
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/shopspring/decimal"
+	"os"
+	"time"
+)
+
+const (
+	// Version is the format version written by this package
+	Version = 1
+
+	TypeContract = "contract"
+
+	StatusUnsigned = "unsigned"
+	StatusSigned   = "signed"
+
+	// clientTxType marks a transaction sent by a client, the node rebuilds it from the payload and the signature
+	clientTxType = 128
+)
+
+var ErrNotSigned = errors.New("transaction isn't signed")
+
+// File is a transaction with its decoded fields
+type File struct {
+	Version     int            `json:"version"`
+	Type        string         `json:"type"`
+	Status      string         `json:"status"`
+	Contract    string         `json:"contract"`
+	ContractID  int            `json:"contract_id"`
+	Params      map[string]any `json:"params"`
+	Expedite    string         `json:"expedite"`
+	EcosystemID int64          `json:"ecosystem_id"`
+	KeyID       int64          `json:"key_id"`
+	Account     string         `json:"account"`
+	NetworkID   int64          `json:"network_id"`
+	Time        int64          `json:"time"`
+	Expires     string         `json:"expires"`
+	Cryptoer    string         `json:"cryptoer"`
+	Hasher      string         `json:"hasher"`
+	PublicKey   string         `json:"public_key,omitempty"`
+	Payload     string         `json:"payload"`
+	Hash        string         `json:"hash,omitempty"`
+	Signature   string         `json:"signature,omitempty"`
+}
+
+// New returns the unsigned transaction calling the contract, the public key is set by Sign
+func New(contract string, header types.Header, params map[string]any, expedite string) (*File, error) {
+	if expedite != "" {
+		// SmartTransaction.Validate checks the network id of a local node, so the expedite is checked here
+		fee, err := decimal.NewFromString(expedite)
+		if err != nil || fee.IsNegative() {
+			return nil, fmt.Errorf("expedite [%s] invalid", expedite)
+		}
+	}
+	header.PublicKey = nil
+	tx := &types.SmartTransaction{
+		Header:   &header,
+		Expedite: expedite,
+		Params:   params,
+	}
+	payload, err := tx.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	f := &File{
+		Version:     Version,
+		Type:        TypeContract,
+		Status:      StatusUnsigned,
+		Contract:    contract,
+		ContractID:  header.ID,
+		Params:      Display(params),
+		Expedite:    expedite,
+		EcosystemID: header.EcosystemID,
+		KeyID:       header.KeyID,
+		Account:     converter.AddressToString(header.KeyID),
+		NetworkID:   header.NetworkID,
+		Time:        header.Time,
+		Expires:     time.Unix(header.Time+consts.MaxTxBack, 0).UTC().Format(time.RFC3339),
+		Payload:     hex.EncodeToString(payload),
+	}
+	return f, nil
+}
+
+// Read reads the file and checks it against its payload
+func Read(filename string) (*File, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var f File
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("tx file %s: %w", filename, err)
+	}
+	if f.Version != Version {
+		return nil, fmt.Errorf("tx file %s: version %d unsupported, want %d", filename, f.Version, Version)
+	}
+	if f.Type != TypeContract {
+		return nil, fmt.Errorf("tx file %s: type [%s] unsupported", filename, f.Type)
+	}
+	if _, err := f.Transaction(); err != nil {
+		return nil, fmt.Errorf("tx file %s: %w", filename, err)
+	}
+	return &f, nil
+}
+
+// Write saves the file readable only by the user, like the config
+func (f *File) Write(filename string) error {
+	data, err := json.MarshalIndent(f, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(filename, append(data, '\n'), 0600); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of an existing file
+	return os.Chmod(filename, 0600)
+}
+
+// Transaction decodes the payload and checks that the fields of the file match it
+func (f *File) Transaction() (*types.SmartTransaction, error) {
+	payload, err := hex.DecodeString(f.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("payload: %w", err)
+	}
+	tx := &types.SmartTransaction{}
+	if err := tx.Unmarshal(payload); err != nil {
+		return nil, fmt.Errorf("payload: %w", err)
+	}
+	if tx.Header == nil || tx.TxType() != types.SmartContractTxType {
+		return nil, errors.New("payload isn't a contract transaction")
+	}
+	mismatch := func(field string) error {
+		return fmt.Errorf("%s doesn't match the payload, the file was edited", field)
+	}
+	switch {
+	case tx.ID != f.ContractID:
+		return nil, mismatch("contract_id")
+	case tx.EcosystemID != f.EcosystemID:
+		return nil, mismatch("ecosystem_id")
+	case tx.KeyID != f.KeyID:
+		return nil, mismatch("key_id")
+	case converter.AddressToString(tx.KeyID) != f.Account:
+		return nil, mismatch("account")
+	case tx.NetworkID != f.NetworkID:
+		return nil, mismatch("network_id")
+	case tx.Time != f.Time:
+		return nil, mismatch("time")
+	case tx.Expedite != f.Expedite:
+		return nil, mismatch("expedite")
+	case hex.EncodeToString(tx.PublicKey) != f.PublicKey:
+		return nil, mismatch("public_key")
+	}
+	decoded, err := canonical(Display(tx.Params))
+	if err != nil {
+		return nil, err
+	}
+	written, err := canonical(f.Params)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(decoded, written) {
+		return nil, mismatch("params")
+	}
+	return tx, nil
+}
+
+// Sign sets the public key of the private key and signs the payload.
+// The crypto package must be initialized with the algorithms of the file
+func (f *File) Sign(privateKey []byte) error {
+	tx, err := f.Transaction()
+	if err != nil {
+		return err
+	}
+	publicKey, err := crypto.PrivateToPublic(privateKey)
+	if err != nil {
+		return err
+	}
+	if keyID := crypto.Address(publicKey); keyID != f.KeyID {
+		return fmt.Errorf("the private key belongs to key id %d, the transaction to %d", keyID, f.KeyID)
+	}
+	tx.PublicKey = publicKey
+	payload, err := tx.Marshal()
+	if err != nil {
+		return err
+	}
+	hash := crypto.DoubleHash(payload)
+	signature, err := crypto.Sign(privateKey, hash)
+	if err != nil {
+		return err
+	}
+	f.Status = StatusSigned
+	f.PublicKey = hex.EncodeToString(publicKey)
+	f.Payload = hex.EncodeToString(payload)
+	f.Hash = hex.EncodeToString(hash)
+	f.Signature = hex.EncodeToString(signature)
+	return nil
+}
+
+// Data returns the signed transaction as sent to the node.
+// The crypto package must be initialized with the algorithms of the file
+func (f *File) Data() ([]byte, error) {
+	if f.Status != StatusSigned {
+		return nil, ErrNotSigned
+	}
+	payload, err := hex.DecodeString(f.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("payload: %w", err)
+	}
+	signature, err := hex.DecodeString(f.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("signature: %w", err)
+	}
+	publicKey, err := hex.DecodeString(f.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("public_key: %w", err)
+	}
+	hash := crypto.DoubleHash(payload)
+	if hex.EncodeToString(hash) != f.Hash {
+		return nil, errors.New("hash doesn't match the payload")
+	}
+	if ok, err := crypto.Verify(publicKey, hash, signature); err != nil || !ok {
+		return nil, fmt.Errorf("signature invalid: %v", err)
+	}
+	return clientData(payload, signature), nil
+}
+
+// SignTransaction sets the public key of the private key, signs the transaction
+// and returns its hash and the data sent to the node.
+// The crypto package must be initialized with the algorithms of the private key
+func SignTransaction(tx *types.SmartTransaction, privateKey []byte) (hash []byte, data []byte, err error) {
+	tx.PublicKey, err = crypto.PrivateToPublic(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	payload, err := tx.Marshal()
+	if err != nil {
+		return nil, nil, err
+	}
+	hash = crypto.DoubleHash(payload)
+	signature, err := crypto.Sign(privateKey, hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	return hash, clientData(payload, signature), nil
+}
+
+func clientData(payload, signature []byte) []byte {
+	data := append([]byte{clientTxType}, converter.EncodeLengthPlusData(payload)...)
+	return append(data, converter.EncodeLengthPlusData(signature)...)
+}
+
+// Display returns the params as written to the file, bytes are written as 0x hex
+func Display(params map[string]any) map[string]any {
+	out := make(map[string]any, len(params))
+	for k, v := range params {
+		out[k] = display(v)
+	}
+	return out
+}
+
+func display(v any) any {
+	switch val := v.(type) {
+	case []byte:
+		return "0x" + hex.EncodeToString(val)
+	case map[string]any:
+		return Display(val)
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = display(item)
+		}
+		return out
+	}
+	return v
+}
+
+// canonical returns the json of the params with numbers as written, to compare params decoded in different ways
+func canonical(params map[string]any) ([]byte, error) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}